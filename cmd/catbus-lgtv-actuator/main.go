@@ -6,7 +6,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.eth.moe/catbus"
@@ -16,6 +19,27 @@ import (
 	"go.eth.moe/logger"
 )
 
+type (
+	// alertCommand is the JSON payload for the alert topic.
+	alertCommand struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+		Buttons []struct {
+			Label string `json:"label"`
+			// App is an app name from the config, or an app ID, to launch when the button is pressed.
+			App string `json:"app"`
+		} `json:"buttons"`
+		TimeoutSeconds int `json:"timeoutSeconds"`
+	}
+)
+
+const (
+	defaultAlertTimeout = 60 * time.Second
+
+	powerOnTimeout     = 30 * time.Second
+	powerOnRetryPeriod = 2 * time.Second
+)
+
 var (
 	configPath = flag.Custom("config-path", "", "path to config.json", flag.RequiredString)
 )
@@ -54,6 +78,62 @@ func main() {
 				log.AddField("topic", config.Topics.Power)
 				log.Error("could not subscribe to topic")
 			}
+			if config.Topics.URL != "" {
+				if err := client.Subscribe(config.Topics.URL, openURL(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.URL)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Channel != "" {
+				if err := client.Subscribe(config.Topics.Channel, setChannel(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Channel)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Media != "" {
+				if err := client.Subscribe(config.Topics.Media, setMedia(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Media)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Mute != "" {
+				if err := client.Subscribe(config.Topics.Mute, setMute(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Mute)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.SoundOutput != "" {
+				if err := client.Subscribe(config.Topics.SoundOutput, setSoundOutput(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.SoundOutput)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Text != "" {
+				if err := client.Subscribe(config.Topics.Text, insertText(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Text)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Notify != "" {
+				if err := client.Subscribe(config.Topics.Notify, showToast(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Notify)
+					log.Error("could not subscribe to topic")
+				}
+			}
+			if config.Topics.Alert != "" {
+				if err := client.Subscribe(config.Topics.Alert, showAlert(config)); err != nil {
+					log := log.WithError(err)
+					log.AddField("topic", config.Topics.Alert)
+					log.Error("could not subscribe to topic")
+				}
+			}
 			log.Info("subscribed to topics")
 		},
 		DisconnectHandler: func(client catbus.Client, err error) {
@@ -79,44 +159,203 @@ func setApp(config *config.Config) catbus.MessageHandler {
 
 		log.AddField("app-name", msg.Payload)
 
-		appID, ok := config.Apps[msg.Payload]
-		if !ok {
+		app, ok := config.Apps[msg.Payload]
+		if !ok && !config.InputsAsApps {
 			log.Warning("got invalid app name")
 			return
 		}
 
-		log.AddField("app-id", appID)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
 
-		ctx, _ = context.WithTimeout(ctx, 5*time.Second)
-		tv, err := lgtv.Dial(ctx, config.TV.Host, lgtv.DefaultOptions)
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
 		if err != nil {
 			log.WithError(err).Warning("could not connect to TV")
 			return
 		}
+		defer tv.Close()
 		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
 			log.WithError(err).Error("could not register with TV")
-			tv.Close()
 			return
 		}
 
-		if err := tv.SetApp(ctx, appID); err != nil {
+		if !ok {
+			inputs, err := tv.ListInputs(ctx)
+			if err != nil {
+				log.WithError(err).Error("could not list inputs")
+				return
+			}
+			for _, input := range inputs {
+				if input.Label == msg.Payload || (input.Label == "" && input.ID == msg.Payload) {
+					log.AddField("input-id", input.ID)
+
+					if err := tv.SetInput(ctx, input.ID); err != nil {
+						log.WithError(err).Error("could not set input")
+						return
+					}
+					log.Info("set input")
+					return
+				}
+			}
+			log.Warning("got invalid app name")
+			return
+		}
+
+		log.AddField("app-id", app.ID)
+		if app.ContentID != "" {
+			log.AddField("content-id", app.ContentID)
+		}
+
+		params := lgtv.LaunchParams{
+			ContentID: app.ContentID,
+			Params:    app.Params,
+		}
+		if err := tv.LaunchApp(ctx, app.ID, params); err != nil {
 			log.WithError(err).Error("could not set app")
 			return
 		}
 		log.Info("set app")
 	}
 }
+func openURL(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("url", msg.Payload)
+
+		if msg.Payload == "" {
+			log.Info("empty URL, ignoring")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		if err := tv.OpenURL(ctx, msg.Payload); err != nil {
+			log.WithError(err).Error("could not open URL")
+			return
+		}
+		log.Info("opened URL")
+	}
+}
+func setChannel(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("channel-name", msg.Payload)
+
+		idOrNumber, ok := config.Channels[msg.Payload]
+		if !ok {
+			log.Warning("got invalid channel name")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		channels, err := tv.ListChannels(ctx)
+		if err != nil {
+			log.WithError(err).Error("could not list channels")
+			return
+		}
+		var channel lgtv.Channel
+		for _, ch := range channels {
+			if ch.ID == idOrNumber || ch.Number == idOrNumber {
+				channel = ch
+				break
+			}
+		}
+		if channel.ID == "" {
+			log.AddField("channel-id-or-number", idOrNumber)
+			log.Warning("could not find channel on TV")
+			return
+		}
+
+		log.AddField("channel-id", channel.ID)
+		log.AddField("channel-number", channel.Number)
+
+		if err := tv.SetChannel(ctx, channel); err != nil {
+			log.WithError(err).Error("could not set channel")
+			return
+		}
+		log.Info("set channel")
+	}
+}
 func setVolume(config *config.Config) catbus.MessageHandler {
 	return func(_ catbus.Client, msg catbus.Message) {
 		log, ctx := logger.FromContext(context.Background())
 
 		log.AddField("volume-raw", msg.Payload)
 
-		volume, err := strconv.Atoi(msg.Payload)
+		step, volume, relative, err := parseVolume(msg.Payload)
 		if err != nil {
 			log.WithError(err).Warning("got invalid volume")
 			return
 		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		switch {
+		case step > 0:
+			if err := tv.VolumeUp(ctx); err != nil {
+				log.WithError(err).Error("could not raise volume")
+				return
+			}
+			log.Info("raised volume")
+			return
+
+		case step < 0:
+			if err := tv.VolumeDown(ctx); err != nil {
+				log.WithError(err).Error("could not lower volume")
+				return
+			}
+			log.Info("lowered volume")
+			return
+
+		case relative:
+			current, err := tv.Volume(ctx)
+			if err != nil {
+				log.WithError(err).Error("could not get volume")
+				return
+			}
+			log.AddField("volume-previous", current.Percent)
+			volume += current.Percent
+		}
+
 		if volume < 0 {
 			volume = 0
 		}
@@ -126,23 +365,144 @@ func setVolume(config *config.Config) catbus.MessageHandler {
 
 		log.AddField("volume", volume)
 
-		ctx, _ = context.WithTimeout(ctx, 5*time.Second)
-		tv, err := lgtv.Dial(ctx, config.TV.Host, lgtv.DefaultOptions)
+		if err := tv.SetVolume(ctx, volume); err != nil {
+			log.WithError(err).Error("could not set volume")
+			return
+		}
+		log.Info("set volume")
+	}
+}
+
+// parseVolume parses a volume payload, which is one of:
+// "up" or "down", for a single step on the TV, returned as a step of 1 or -1;
+// "+N" or "-N", for a change relative to the current volume;
+// or "N", for an absolute percentage.
+func parseVolume(raw string) (step, volume int, relative bool, err error) {
+	switch {
+	case raw == "up":
+		return 1, 0, false, nil
+	case raw == "down":
+		return -1, 0, false, nil
+	case strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-"):
+		volume, err := strconv.Atoi(raw)
+		return 0, volume, true, err
+	default:
+		volume, err := strconv.Atoi(raw)
+		return 0, volume, false, err
+	}
+}
+func setSoundOutput(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("sound-output", msg.Payload)
+
+		if msg.Payload == "" {
+			log.Warning("got empty sound output")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
 		if err != nil {
 			log.WithError(err).Warning("could not connect to TV")
 			return
 		}
+		defer tv.Close()
 		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
 			log.WithError(err).Error("could not register with TV")
-			tv.Close()
 			return
 		}
 
-		if err := tv.SetVolume(ctx, volume); err != nil {
-			log.WithError(err).Error("could not set volume")
+		if err := tv.SetSoundOutput(ctx, lgtv.SoundOutput(msg.Payload)); err != nil {
+			log.WithError(err).Error("could not set sound output")
 			return
 		}
-		log.Info("set volume")
+		log.Info("set sound output")
+	}
+}
+func setMute(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("mute", msg.Payload)
+
+		var mute bool
+		switch msg.Payload {
+		case "on":
+			mute = true
+		case "off":
+			mute = false
+		default:
+			log.Warning("got invalid mute, must be \"on\" or \"off\"")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		if err := tv.SetMute(ctx, mute); err != nil {
+			log.WithError(err).Error("could not set mute")
+			return
+		}
+		log.Info("set mute")
+	}
+}
+func setMedia(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("media", msg.Payload)
+
+		var control func(lgtv.Client, context.Context) error
+		switch msg.Payload {
+		case "play":
+			control = lgtv.Client.Play
+		case "pause":
+			control = lgtv.Client.Pause
+		case "stop":
+			control = lgtv.Client.Stop
+		case "rewind":
+			control = lgtv.Client.Rewind
+		case "fast-forward":
+			control = lgtv.Client.FastForward
+		default:
+			log.Warning("got invalid media control")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		if err := control(tv, ctx); err != nil {
+			log.WithError(err).Error("could not control media")
+			return
+		}
+		log.Info("controlled media")
 	}
 }
 func setPower(config *config.Config) catbus.MessageHandler {
@@ -151,20 +511,46 @@ func setPower(config *config.Config) catbus.MessageHandler {
 
 		log.AddField("power", msg.Payload)
 
-		if msg.Payload != "off" {
-			log.Info("power is not \"off\", ignoring")
+		switch msg.Payload {
+		case "on":
+			// Turning on waits for the TV to boot, so do not hold up other messages.
+			go turnOn(ctx, config)
+			return
+		case "off", "screen-off":
+		default:
+			log.Info("power is not \"on\", \"off\", or \"screen-off\", ignoring")
 			return
 		}
 
-		ctx, _ = context.WithTimeout(ctx, 5*time.Second)
-		tv, err := lgtv.Dial(ctx, config.TV.Host, lgtv.DefaultOptions)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
 		if err != nil {
 			log.WithError(err).Warning("could not connect to TV")
 			return
 		}
+		defer tv.Close()
 		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
 			log.WithError(err).Error("could not register with TV")
-			tv.Close()
+			return
+		}
+
+		// The observer publishes the TV's own power state to the same topic, so do nothing if it is already there.
+		if state, err := tv.PowerState(ctx); err == nil {
+			if state == lgtv.PowerStateStandby || (msg.Payload == "screen-off" && state == lgtv.PowerStateScreenOff) {
+				log.AddField("power-state", state)
+				log.Info("TV is already off")
+				return
+			}
+		}
+
+		if msg.Payload == "screen-off" {
+			if err := tv.TurnOffScreen(ctx); err != nil {
+				log.WithError(err).Error("could not turn TV screen off")
+				return
+			}
+			log.Info("turned TV screen off")
 			return
 		}
 
@@ -175,3 +561,229 @@ func setPower(config *config.Config) catbus.MessageHandler {
 		log.Info("turned TV off")
 	}
 }
+
+// turnOn sends Wake-on-LAN packets until the TV reports that it is on.
+func turnOn(ctx context.Context, config *config.Config) {
+	log, ctx := logger.FromContext(ctx)
+
+	if config.TV.MAC == "" {
+		log.Warning("cannot turn TV on without a MAC address in the config")
+		return
+	}
+	log.AddField("mac", config.TV.MAC)
+
+	ctx, cancel := context.WithTimeout(ctx, powerOnTimeout)
+	defer cancel()
+
+	for {
+		// Packets can get lost, so send one for every attempt.
+		if err := lgtv.WakeOnLAN(config.TV.MAC, config.TV.Broadcast); err != nil {
+			log.WithError(err).Error("could not send Wake-on-LAN packet")
+			return
+		}
+
+		on, err := wake(ctx, config)
+		if on {
+			log.Info("turned TV on")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				log = log.WithError(err)
+			}
+			log.Error("TV did not turn on after Wake-on-LAN")
+			return
+		case <-time.After(powerOnRetryPeriod):
+		}
+	}
+}
+
+// wake returns whether the TV is on, and turns its screen on if only that is off.
+// TVs in standby can accept connections, so it checks the TV's power state.
+func wake(ctx context.Context, config *config.Config) (bool, error) {
+	dialCtx, cancelDial := context.WithTimeout(ctx, powerOnRetryPeriod)
+	defer cancelDial()
+
+	tv, err := lgtv.Dial(dialCtx, config.TV.Host, config.TVOptions())
+	if err != nil {
+		return false, err
+	}
+	defer tv.Close()
+	if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+		return false, fmt.Errorf("could not register with TV: %w", err)
+	}
+
+	state, err := tv.PowerState(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not get TV power state: %w", err)
+	}
+	switch state {
+	case lgtv.PowerStateOn:
+		return true, nil
+	case lgtv.PowerStateScreenOff:
+		// The next attempt checks that it worked.
+		if err := tv.TurnOnScreen(ctx); err != nil {
+			return false, fmt.Errorf("could not turn TV screen on: %w", err)
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("TV power state is %v", state)
+	}
+}
+func insertText(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		// A trailing newline presses enter after the text, e.g. to submit a search.
+		text := strings.TrimSuffix(msg.Payload, "\n")
+		enter := text != msg.Payload
+
+		log.AddField("text", text)
+		log.AddField("enter", enter)
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		if text != "" {
+			if err := tv.InsertText(ctx, text); err != nil {
+				log.WithError(err).Error("could not insert text")
+				return
+			}
+		}
+		if enter {
+			if err := tv.SendEnter(ctx); err != nil {
+				log.WithError(err).Error("could not send enter")
+				return
+			}
+		}
+		log.Info("inserted text")
+	}
+}
+func showToast(config *config.Config) catbus.MessageHandler {
+	return func(_ catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("message", msg.Payload)
+
+		if msg.Payload == "" {
+			log.Info("empty message, ignoring")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+		if err != nil {
+			log.WithError(err).Warning("could not connect to TV")
+			return
+		}
+		defer tv.Close()
+		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+			log.WithError(err).Error("could not register with TV")
+			return
+		}
+
+		if err := tv.ShowToast(ctx, msg.Payload, lgtv.ToastOptions{}); err != nil {
+			log.WithError(err).Error("could not show toast")
+			return
+		}
+		log.Info("showed toast")
+	}
+}
+func showAlert(config *config.Config) catbus.MessageHandler {
+	return func(client catbus.Client, msg catbus.Message) {
+		log, ctx := logger.FromContext(context.Background())
+
+		log.AddField("alert-raw", msg.Payload)
+
+		cmd := alertCommand{}
+		if err := json.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
+			log.WithError(err).Warning("got invalid alert")
+			return
+		}
+
+		alert := lgtv.Alert{
+			Title:   cmd.Title,
+			Message: cmd.Message,
+			Timeout: time.Duration(cmd.TimeoutSeconds) * time.Second,
+		}
+		if alert.Timeout <= 0 {
+			alert.Timeout = defaultAlertTimeout
+		}
+		for _, button := range cmd.Buttons {
+			// Buttons without apps only close the alert, which the TV does not report until the alert times out.
+			if button.App == "" {
+				log.AddField("button", button.Label)
+				log.Warning("got alert button without an app, ignoring")
+				return
+			}
+			appID := button.App
+			if app, ok := config.Apps[button.App]; ok {
+				appID = app.ID
+			}
+			alert.Buttons = append(alert.Buttons, lgtv.AlertButton{
+				Label: button.Label,
+				AppID: appID,
+			})
+		}
+
+		// Alerts wait for the viewer, so do not hold up other messages.
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, alert.Timeout+10*time.Second)
+			defer cancel()
+
+			tv, err := lgtv.Dial(ctx, config.TV.Host, config.TVOptions())
+			if err != nil {
+				log.WithError(err).Warning("could not connect to TV")
+				return
+			}
+			defer tv.Close()
+			if _, err := tv.Register(ctx, config.TV.Key); err != nil {
+				log.WithError(err).Error("could not register with TV")
+				return
+			}
+
+			result, err := tv.ShowAlert(ctx, alert)
+			if err != nil {
+				log.WithError(err).Error("could not show alert")
+				return
+			}
+
+			var response string
+			switch {
+			case result.Button >= 0:
+				response = alert.Buttons[result.Button].Label
+			case result.TimedOut:
+				response = "timed-out"
+			default:
+				response = "dismissed"
+			}
+			log.AddField("alert-response", response)
+			log.Info("showed alert")
+
+			if config.Topics.AlertResponse == "" {
+				return
+			}
+			log.AddField("topic", config.Topics.AlertResponse)
+			if err := client.Publish(config.Topics.AlertResponse, catbus.DontRetain, response); err != nil {
+				log.WithError(err).Error("could not publish to Catbus")
+				return
+			}
+			log.Info("published to Catbus")
+		}()
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.eth.moe/catbus"
@@ -21,6 +22,14 @@ import (
 	"go.eth.moe/logger"
 )
 
+type (
+	// inputs are the TV's external inputs, by their labels on the TV.
+	inputs struct {
+		sync.Mutex
+		appIDForLabel map[string]string
+	}
+)
+
 var (
 	configPath = flag.Custom("config-path", "", "path to config.json", flag.RequiredString)
 )
@@ -38,13 +47,21 @@ func main() {
 		log.WithError(err).Fatal("could not load config")
 	}
 
+	tvInputs := &inputs{}
+
 	client := catbus.NewClient(config.BrokerURI, catbus.ClientOptions{
 		ConnectHandler: func(client catbus.Client) {
 			log := logger.Background()
 			log.AddField("broker-uri", config.BrokerURI)
 			log.Info("connected to Catbus")
 
-			publishAppNames(config, client)
+			publishAppNames(config, client, tvInputs)
+			if config.Topics.Channel != "" {
+				publishChannelNames(config, client)
+			}
+			if config.Topics.SoundOutput != "" {
+				publishSoundOutputNames(config, client)
+			}
 		},
 		DisconnectHandler: func(client catbus.Client, err error) {
 			log := logger.Background()
@@ -65,78 +82,179 @@ func main() {
 		}
 	}()
 
-	for {
-		log, ctx := logger.FromContext(context.Background())
-		ctx, _ = context.WithTimeout(ctx, 10*time.Second)
+	log.AddField("tv", config.TV.Host)
 
-		log.AddField("tv", config.TV.Host)
+	log.Info("connecting to TV")
+	tv := lgtv.DialPersistent(context.Background(), config.TV.Host, config.TV.Key, lgtv.PersistentOptions{
+		Options: config.TVOptions(),
 
-		log.Info("connecting to TV")
-		tv, err := lgtv.Dial(ctx, config.TV.Host, lgtv.DefaultOptions)
-		if err != nil {
-			log.WithError(err).Info("could not connect to TV")
-			continue
+		OnConnect: func(ctx context.Context, tv lgtv.Client) {
+			log, ctx := log.Fork(ctx)
+			log.Info("connected to TV")
+
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+
+			if config.Topics.Info != "" {
+				if info, err := tv.SystemInfo(ctx); err != nil {
+					log.WithError(err).Error("could not get system info")
+				} else {
+					publishSystemInfo(config, client, info)
+				}
+			}
+
+			if config.InputsAsApps {
+				if inputs, err := tv.ListInputs(ctx); err != nil {
+					log.WithError(err).Error("could not list inputs")
+				} else {
+					tvInputs.set(inputs)
+					publishAppNames(config, client, tvInputs)
+				}
+			}
+		},
+
+		OnConnectError: func(err error) {
+			log.WithError(err).Warning("could not connect to TV, retrying")
+		},
+
+		OnDisconnect: func(err error) {
+			if err != nil {
+				log.WithError(err).Error("disconnected from TV")
+			} else {
+				log.Info("disconnected from TV")
+			}
+		},
+	})
+
+	appSubscription, err := tv.SubscribeApp(context.Background(), func(app lgtv.App) {
+		log, _ := log.Fork(context.Background())
+		log.AddField("app-id", app.ID)
+
+		if app.ID == "" {
+			log.Info("empty app ID, TV about to turn off")
+			return
 		}
-		log.Info("connected to TV")
 
-		if _, err := tv.Register(ctx, config.TV.Key); err != nil {
-			log.WithError(err).Error("could not register with TV")
-			tv.Close()
-			continue
+		name, ok := config.AppNameForID(app.ID)
+		if !ok {
+			name, ok = tvInputs.labelForAppID(app.ID)
+		}
+		if !ok {
+			name = app.ID
+		}
+		log.AddField("app-name", name)
+
+		log.AddField("topic", config.Topics.App)
+		if err := client.Publish(config.Topics.App, catbus.Retain, name); err != nil {
+			log.WithError(err).Error("could not publish to Catbus")
+			return
+		}
+		log.Info("published to Catbus")
+	})
+	watchSubscription("app", appSubscription, err)
+
+	// Volume and mute come from the same events, so subscribe once for both.
+	volumeSubscription, err := tv.SubscribeVolume(context.Background(), func(v lgtv.Volume) {
+		publishVolume(config, client, v)
+		if config.Topics.Mute != "" {
+			publishMute(config, client, v.Muted)
 		}
-		log.Info("registered with TV")
+	})
+	watchSubscription("volume", volumeSubscription, err)
 
-		tv.SubscribeApp(func(app lgtv.App) {
+	if config.Topics.Channel != "" {
+		channelSubscription, err := tv.SubscribeChannel(context.Background(), func(ch lgtv.Channel) {
 			log, _ := log.Fork(context.Background())
-			log.AddField("app-id", app.ID)
+			log.AddField("channel-id", ch.ID)
+			log.AddField("channel-number", ch.Number)
 
-			if app.ID == "" {
-				log.Info("empty app ID, TV about to turn off")
-				return
+			name, ok := config.ChannelNameForChannel(ch.ID, ch.Number)
+			if !ok {
+				name = ch.Number
 			}
+			log.AddField("channel-name", name)
 
-			name, ok := config.AppNameForID(app.ID)
-			if !ok {
-				name = app.ID
+			log.AddField("topic", config.Topics.Channel)
+			if err := client.Publish(config.Topics.Channel, catbus.Retain, name); err != nil {
+				log.WithError(err).Error("could not publish to Catbus")
+				return
 			}
-			log.AddField("app-name", name)
+			log.Info("published to Catbus")
+		})
+		watchSubscription("channel", channelSubscription, err)
+	}
+
+	if config.Topics.MediaState != "" {
+		mediaStateSubscription, err := tv.SubscribeMediaState(context.Background(), func(state lgtv.MediaState) {
+			log, _ := log.Fork(context.Background())
+			log.AddField("app-id", state.AppID)
+			log.AddField("media-state", state.PlayState)
+			log.AddField("topic", config.Topics.MediaState)
 
-			log.AddField("topic", config.Topics.App)
-			if err := client.Publish(config.Topics.App, catbus.Retain, name); err != nil {
+			if err := client.Publish(config.Topics.MediaState, catbus.Retain, string(state.PlayState)); err != nil {
 				log.WithError(err).Error("could not publish to Catbus")
 				return
 			}
 			log.Info("published to Catbus")
 		})
+		watchSubscription("media state", mediaStateSubscription, err)
+	}
 
-		tv.SubscribeVolume(func(v lgtv.Volume) {
+	if config.Topics.SoundOutput != "" {
+		soundOutputSubscription, err := tv.SubscribeSoundOutput(context.Background(), func(output lgtv.SoundOutput) {
 			log, _ := log.Fork(context.Background())
-			log.AddField("volume", v.Percent)
-			log.AddField("topic", config.Topics.Volume)
+			log.AddField("sound-output", output)
+			log.AddField("topic", config.Topics.SoundOutput)
 
-			if err := client.Publish(config.Topics.Volume, catbus.Retain, strconv.Itoa(v.Percent)); err != nil {
+			if err := client.Publish(config.Topics.SoundOutput, catbus.Retain, string(output)); err != nil {
 				log.WithError(err).Error("could not publish to Catbus")
 				return
 			}
 			log.Info("published to Catbus")
 		})
+		watchSubscription("sound output", soundOutputSubscription, err)
+	}
 
-		log.Info("waiting for TV to disconnect")
-		if err := tv.Wait(); err != nil {
-			log.WithError(err).Error("disconnected from TV")
-		} else {
-			log.Info("disconnected from TV")
-		}
+	if config.Topics.Power != "" {
+		powerStateSubscription, err := tv.SubscribePowerState(context.Background(), func(state lgtv.PowerState) {
+			publishPowerState(config, client, state)
+		})
+		watchSubscription("power state", powerStateSubscription, err)
 	}
+
+	// The TV reconnects forever, so block forever.
+	_ = tv.Wait()
+}
+
+// watchSubscription logs if the TV rejects a subscription, or if it ends; errors after that are retried on each reconnect.
+func watchSubscription(name string, subscription lgtv.Subscription, err error) {
+	log := logger.Background()
+	log.AddField("subscription", name)
+
+	if err != nil {
+		log.WithError(err).Error("could not subscribe to TV")
+		return
+	}
+	go func() {
+		<-subscription.Done()
+		if err := subscription.Err(); err != nil {
+			log.WithError(err).Error("subscription to TV ended")
+		}
+	}()
 }
 
-func publishAppNames(config *config.Config, client catbus.Client) {
+func publishAppNames(config *config.Config, client catbus.Client, tvInputs *inputs) {
 	log := logger.Background()
 
 	var appNames []string
 	for appName := range config.Apps {
 		appNames = append(appNames, appName)
 	}
+	for _, label := range tvInputs.labels() {
+		if _, ok := config.Apps[label]; !ok {
+			appNames = append(appNames, label)
+		}
+	}
 	sort.Strings(appNames)
 	appNamesTopic := path.Join(config.Topics.App, "values")
 	if err := client.Publish(appNamesTopic, catbus.Retain, strings.Join(appNames, "\n")); err != nil {
@@ -145,3 +263,148 @@ func publishAppNames(config *config.Config, client catbus.Client) {
 	}
 	log.Info("published app names to Catbus")
 }
+
+func publishSystemInfo(config *config.Config, client catbus.Client, info lgtv.SystemInfo) {
+	log := logger.Background()
+
+	fields := map[string]string{
+		"model":            info.Model,
+		"serial-number":    info.SerialNumber,
+		"webos-version":    info.WebOSVersion,
+		"firmware-version": info.FirmwareVersion,
+		"country":          info.Country,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		topic := path.Join(config.Topics.Info, name)
+		if err := client.Publish(topic, catbus.Retain, value); err != nil {
+			log := log.WithError(err)
+			log.AddField("topic", topic)
+			log.Error("could not publish system info to Catbus")
+			return
+		}
+	}
+	log.Info("published system info to Catbus")
+}
+
+func publishVolume(config *config.Config, client catbus.Client, v lgtv.Volume) {
+	log := logger.Background()
+	log.AddField("volume", v.Percent)
+	log.AddField("topic", config.Topics.Volume)
+
+	if err := client.Publish(config.Topics.Volume, catbus.Retain, strconv.Itoa(v.Percent)); err != nil {
+		log.WithError(err).Error("could not publish to Catbus")
+		return
+	}
+	log.Info("published to Catbus")
+}
+
+func publishMute(config *config.Config, client catbus.Client, muted bool) {
+	log := logger.Background()
+
+	mute := "off"
+	if muted {
+		mute = "on"
+	}
+	log.AddField("mute", mute)
+	log.AddField("topic", config.Topics.Mute)
+
+	if err := client.Publish(config.Topics.Mute, catbus.Retain, mute); err != nil {
+		log.WithError(err).Error("could not publish to Catbus")
+		return
+	}
+	log.Info("published to Catbus")
+}
+
+// publishPowerState publishes the TV's power state in the same words the actuator accepts, because they share a topic.
+func publishPowerState(config *config.Config, client catbus.Client, state lgtv.PowerState) {
+	log := logger.Background()
+
+	power := string(state)
+	if state == lgtv.PowerStateStandby {
+		power = "off"
+	}
+	log.AddField("power", power)
+	log.AddField("topic", config.Topics.Power)
+
+	if err := client.Publish(config.Topics.Power, catbus.Retain, power); err != nil {
+		log.WithError(err).Error("could not publish to Catbus")
+		return
+	}
+	log.Info("published to Catbus")
+}
+
+func publishChannelNames(config *config.Config, client catbus.Client) {
+	log := logger.Background()
+
+	var channelNames []string
+	for channelName := range config.Channels {
+		channelNames = append(channelNames, channelName)
+	}
+	sort.Strings(channelNames)
+	channelNamesTopic := config.Topics.ChannelValues
+	if channelNamesTopic == "" {
+		channelNamesTopic = path.Join(config.Topics.Channel, "values")
+	}
+	if err := client.Publish(channelNamesTopic, catbus.Retain, strings.Join(channelNames, "\n")); err != nil {
+		log.WithError(err).Error("could not publish channel names to Catbus")
+		return
+	}
+	log.Info("published channel names to Catbus")
+}
+
+func (i *inputs) set(tvInputs []lgtv.Input) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.appIDForLabel = map[string]string{}
+	for _, input := range tvInputs {
+		label := input.Label
+		if label == "" {
+			label = input.ID
+		}
+		i.appIDForLabel[label] = input.AppID
+	}
+}
+func (i *inputs) labels() []string {
+	i.Lock()
+	defer i.Unlock()
+
+	var labels []string
+	for label := range i.appIDForLabel {
+		labels = append(labels, label)
+	}
+	return labels
+}
+func (i *inputs) labelForAppID(id string) (string, bool) {
+	i.Lock()
+	defer i.Unlock()
+
+	for label, id2 := range i.appIDForLabel {
+		if id2 == id {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+func publishSoundOutputNames(config *config.Config, client catbus.Client) {
+	log := logger.Background()
+
+	var outputNames []string
+	for _, output := range lgtv.SoundOutputs {
+		outputNames = append(outputNames, string(output))
+	}
+	sort.Strings(outputNames)
+	outputNamesTopic := config.Topics.SoundOutputValues
+	if outputNamesTopic == "" {
+		outputNamesTopic = path.Join(config.Topics.SoundOutput, "values")
+	}
+	if err := client.Publish(outputNamesTopic, catbus.Retain, strings.Join(outputNames, "\n")); err != nil {
+		log.WithError(err).Error("could not publish sound output names to Catbus")
+		return
+	}
+	log.Info("published sound output names to Catbus")
+}
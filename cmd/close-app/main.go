@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+// Binary close-app closes a running app on a WebOS LG TV.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go.eth.moe/catbus-lgtv/config"
+	"go.eth.moe/catbus-lgtv/lgtv"
+)
+
+var (
+	appID      = flag.String("app-id", "", "app ID to close")
+	configPath = flag.String("config-path", "", "path to config.json")
+)
+
+func main() {
+	flag.Parse()
+
+	if *configPath == "" || *appID == "" {
+		log.Fatal("must set --config-path and --app-id")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	log.Print("connecting to TV")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
+	if err != nil {
+		log.Fatalf("could not dial TV: %v", err)
+	}
+
+	log.Print("registering with TV")
+	if _, err := tv.Register(ctx, cfg.TV.Key); err != nil {
+		log.Fatalf("could not register with TV: %v", err)
+	}
+
+	log.Print("closing app")
+	if err := tv.CloseApp(ctx, *appID); err != nil {
+		log.Fatalf("could not close app: %v", err)
+	}
+}
@@ -34,7 +34,7 @@ func main() {
 
 	log.Print("connecting to TV")
 	ctx, _ := context.WithTimeout(context.Background(), 60*time.Second)
-	tv, err := lgtv.Dial(ctx, cfg.TV.Host, lgtv.DefaultOptions)
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
 	if err != nil {
 		log.Fatalf("could not dial TV: %v", err)
 	}
@@ -44,10 +44,14 @@ func main() {
 		log.Fatalf("could not register with TV: %v", err)
 	}
 
-	tv.SubscribeApp(func(a lgtv.App) {
-		fmt.Println(a)
-	})
+	apps, sub, err := tv.StreamApp(context.Background(), lgtv.DefaultStreamOptions)
+	if err != nil {
+		log.Fatalf("could not subscribe to app: %v", err)
+	}
 
-	// block forever.
-	select {}
+	// the stream closes when the TV goes away.
+	for a := range apps {
+		fmt.Println(a)
+	}
+	log.Fatalf("subscription ended: %v", sub.Err())
 }
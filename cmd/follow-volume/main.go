@@ -34,7 +34,7 @@ func main() {
 
 	log.Printf("connecting to TV")
 	ctx, _ := context.WithTimeout(context.Background(), 60*time.Second)
-	tv, err := lgtv.Dial(ctx, cfg.TV.Host, lgtv.DefaultOptions)
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
 	if err != nil {
 		log.Fatalf("could not dial TV: %v", err)
 	}
@@ -42,10 +42,14 @@ func main() {
 		log.Fatalf("could not register with TV: %v", err)
 	}
 
-	tv.SubscribeVolume(func(v lgtv.Volume) {
-		fmt.Println(v)
-	})
+	volumes, sub, err := tv.StreamVolume(context.Background(), lgtv.DefaultStreamOptions)
+	if err != nil {
+		log.Fatalf("could not subscribe to volume: %v", err)
+	}
 
-	// block forever.
-	select {}
+	// the stream closes when the TV goes away.
+	for v := range volumes {
+		fmt.Println(v)
+	}
+	log.Fatalf("subscription ended: %v", sub.Err())
 }
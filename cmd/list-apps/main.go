@@ -34,7 +34,7 @@ func main() {
 
 	log.Printf("connecting to TV")
 	ctx, _ := context.WithTimeout(context.Background(), 60*time.Second)
-	tv, err := lgtv.Dial(ctx, cfg.TV.Host, lgtv.DefaultOptions)
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
 	if err != nil {
 		log.Fatalf("could not dial TV: %v", err)
 	}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+// Binary open-url opens a URL in the web browser of a WebOS LG TV.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go.eth.moe/catbus-lgtv/config"
+	"go.eth.moe/catbus-lgtv/lgtv"
+)
+
+var (
+	url        = flag.String("url", "", "URL to open")
+	configPath = flag.String("config-path", "", "path to config.json")
+)
+
+func main() {
+	flag.Parse()
+
+	if *configPath == "" || *url == "" {
+		log.Fatal("must set --config-path and --url")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	log.Print("connecting to TV")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
+	if err != nil {
+		log.Fatalf("could not dial TV: %v", err)
+	}
+
+	log.Print("registering with TV")
+	if _, err := tv.Register(ctx, cfg.TV.Key); err != nil {
+		log.Fatalf("could not register with TV: %v", err)
+	}
+
+	log.Print("opening URL")
+	if err := tv.OpenURL(ctx, *url); err != nil {
+		log.Fatalf("could not open URL: %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"flag"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.eth.moe/catbus-lgtv/config"
@@ -16,18 +18,35 @@ import (
 )
 
 var (
-	volumePercent = flag.Int("volume-percent", -1, "volume percent to set")
+	volumePercent = flag.String("volume-percent", "", "volume percent to set, a relative change such as +5 or -10, or up or down")
 	configPath    = flag.String("config-path", "", "path to config.json")
 )
 
 func main() {
 	flag.Parse()
 
-	if *configPath == "" || *volumePercent == -1 {
+	if *configPath == "" || *volumePercent == "" {
 		log.Fatal("must set --config-path and --volume-percent")
 	}
-	if !(0 <= *volumePercent && *volumePercent <= 100) {
-		log.Fatalf("--volume-percent must be within 0 and 100, got %v", *volumePercent)
+
+	var step, volume int
+	var relative bool
+	switch {
+	case *volumePercent == "up":
+		step = 1
+	case *volumePercent == "down":
+		step = -1
+	default:
+		relative = strings.HasPrefix(*volumePercent, "+") || strings.HasPrefix(*volumePercent, "-")
+
+		var err error
+		volume, err = strconv.Atoi(*volumePercent)
+		if err != nil {
+			log.Fatalf("--volume-percent must be a number, +N, -N, up, or down, got %v", *volumePercent)
+		}
+		if !relative && !(0 <= volume && volume <= 100) {
+			log.Fatalf("--volume-percent must be within 0 and 100, got %v", volume)
+		}
 	}
 
 	cfg, err := config.Load(*configPath)
@@ -37,7 +56,7 @@ func main() {
 
 	log.Print("connecting to TV")
 	ctx, _ := context.WithTimeout(context.Background(), 60*time.Second)
-	tv, err := lgtv.Dial(ctx, cfg.TV.Host, lgtv.DefaultOptions)
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
 	if err != nil {
 		log.Fatalf("could not dial TV: %v", err)
 	}
@@ -47,6 +66,38 @@ func main() {
 		log.Fatalf("could not register with TV: %v", err)
 	}
 
+	switch {
+	case step > 0:
+		log.Print("raising volume")
+		if err := tv.VolumeUp(ctx); err != nil {
+			log.Fatalf("could not raise volume: %v", err)
+		}
+		return
+
+	case step < 0:
+		log.Print("lowering volume")
+		if err := tv.VolumeDown(ctx); err != nil {
+			log.Fatalf("could not lower volume: %v", err)
+		}
+		return
+
+	case relative:
+		current, err := tv.Volume(ctx)
+		if err != nil {
+			log.Fatalf("could not get volume: %v", err)
+		}
+		volume += current.Percent
+	}
+
+	if volume < 0 {
+		volume = 0
+	}
+	if volume > 100 {
+		volume = 100
+	}
+
 	log.Print("setting volume")
-	tv.SetVolume(ctx, *volumePercent)
+	if err := tv.SetVolume(ctx, volume); err != nil {
+		log.Fatalf("could not set volume: %v", err)
+	}
 }
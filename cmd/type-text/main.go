@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+// Binary type-text types text into the on-screen keyboard of a WebOS LG TV.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"go.eth.moe/catbus-lgtv/config"
+	"go.eth.moe/catbus-lgtv/lgtv"
+)
+
+var (
+	text       = flag.String("text", "", "text to type")
+	enter      = flag.Bool("enter", false, "press enter after typing")
+	configPath = flag.String("config-path", "", "path to config.json")
+)
+
+func main() {
+	flag.Parse()
+
+	if *configPath == "" || (*text == "" && !*enter) {
+		log.Fatal("must set --config-path and --text or --enter")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	log.Print("connecting to TV")
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tv, err := lgtv.Dial(ctx, cfg.TV.Host, cfg.TVOptions())
+	if err != nil {
+		log.Fatalf("could not dial TV: %v", err)
+	}
+
+	log.Print("registering with TV")
+	if _, err := tv.Register(ctx, cfg.TV.Key); err != nil {
+		log.Fatalf("could not register with TV: %v", err)
+	}
+
+	if *text != "" {
+		log.Print("typing text")
+		if err := tv.InsertText(ctx, *text); err != nil {
+			log.Fatalf("could not type text: %v", err)
+		}
+	}
+	if *enter {
+		log.Print("pressing enter")
+		if err := tv.SendEnter(ctx); err != nil {
+			log.Fatalf("could not press enter: %v", err)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+
+	"go.eth.moe/catbus-lgtv/lgtv"
 )
 
 type (
@@ -18,28 +20,101 @@ type (
 		TV struct {
 			Host string `json:"host"`
 			Key  string `json:"key"`
+
+			// MAC and Broadcast are for turning the TV on with Wake-on-LAN.
+			MAC       string `json:"mac"`
+			Broadcast string `json:"broadcast"`
+
+			// Scheme, Port, CertificateFingerprint, and InsecureSkipVerify are as in lgtv.Options.
+			Scheme                 string `json:"scheme"`
+			Port                   int    `json:"port"`
+			CertificateFingerprint string `json:"certificateFingerprint"`
+			InsecureSkipVerify     bool   `json:"insecureSkipVerify"`
 		} `json:"tv"`
 
 		Topics struct {
-			App       string `json:"app"`
-			AppValues string `json:"appValues"`
-			Power     string `json:"power"`
-			Volume    string `json:"volume"`
+			Alert             string `json:"alert"`
+			AlertResponse     string `json:"alertResponse"`
+			App               string `json:"app"`
+			AppValues         string `json:"appValues"`
+			Channel           string `json:"channel"`
+			ChannelValues     string `json:"channelValues"`
+			Info              string `json:"info"`
+			Media             string `json:"media"`
+			MediaState        string `json:"mediaState"`
+			Mute              string `json:"mute"`
+			Notify            string `json:"notify"`
+			Power             string `json:"power"`
+			SoundOutput       string `json:"soundOutput"`
+			SoundOutputValues string `json:"soundOutputValues"`
+			Text              string `json:"text"`
+			URL               string `json:"url"`
+			Volume            string `json:"volume"`
 		} `json:"topics"`
 
-		Apps map[string]string `json:"apps"`
+		Apps     map[string]App    `json:"apps"`
+		Channels map[string]string `json:"channels"`
+
+		// InputsAsApps adds the TV's external inputs to Apps, named by their labels on the TV.
+		InputsAsApps bool `json:"inputsAsApps"`
+	}
+
+	// App is an app ID with optional launch parameters, e.g. to deep-link into content.
+	// In JSON, it is either an object, or just the app ID as a string.
+	App struct {
+		ID        string                 `json:"id"`
+		ContentID string                 `json:"contentId"`
+		Params    map[string]interface{} `json:"params"`
 	}
 )
 
+// AppNameForID returns the name for an app ID, preferring an app without launch parameters.
 func (c *Config) AppNameForID(id string) (string, bool) {
-	for name, id2 := range c.Apps {
-		if id2 == id {
+	var nameWithParams string
+	for name, app := range c.Apps {
+		if app.ID != id {
+			continue
+		}
+		if app.ContentID == "" && len(app.Params) == 0 {
+			return name, true
+		}
+		nameWithParams = name
+	}
+	return nameWithParams, nameWithParams != ""
+}
+
+func (a *App) UnmarshalJSON(src []byte) error {
+	var id string
+	if err := json.Unmarshal(src, &id); err == nil {
+		*a = App{ID: id}
+		return nil
+	}
+
+	// app has no methods, so this does not recurse.
+	type app App
+	return json.Unmarshal(src, (*app)(a))
+}
+
+// ChannelNameForChannel returns the name for a channel, matching either its ID or its number.
+func (c *Config) ChannelNameForChannel(id, number string) (string, bool) {
+	for name, idOrNumber := range c.Channels {
+		if idOrNumber == id || idOrNumber == number {
 			return name, true
 		}
 	}
 	return "", false
 }
 
+// TVOptions returns lgtv.DefaultOptions with the TV's connection settings.
+func (c *Config) TVOptions() lgtv.Options {
+	opts := lgtv.DefaultOptions
+	opts.Scheme = c.TV.Scheme
+	opts.Port = c.TV.Port
+	opts.CertificateFingerprint = c.TV.CertificateFingerprint
+	opts.InsecureSkipVerify = c.TV.InsecureSkipVerify
+	return opts
+}
+
 func Load(path string) (*Config, error) {
 	src, err := ioutil.ReadFile(path)
 	if err != nil {
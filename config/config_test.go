@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadApps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	src := `{
+		"apps": {
+			"Netflix": "netflix",
+			"Netflix Show": {"id": "netflix", "contentId": "m=123", "params": {"foo": "bar"}}
+		}
+	}`
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatalf("could not write config: %v", err)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("could not load config: %v", err)
+	}
+
+	want := map[string]App{
+		"Netflix": {ID: "netflix"},
+		"Netflix Show": {
+			ID:        "netflix",
+			ContentID: "m=123",
+			Params:    map[string]interface{}{"foo": "bar"},
+		},
+	}
+	if !reflect.DeepEqual(config.Apps, want) {
+		t.Errorf("got apps %+v, want %+v", config.Apps, want)
+	}
+
+	// Apps is a map, so check more than one iteration order.
+	for i := 0; i < 20; i++ {
+		if name, ok := config.AppNameForID("netflix"); !ok || name != "Netflix" {
+			t.Fatalf("AppNameForID(netflix) = %q, %v, want %q, true", name, ok, "Netflix")
+		}
+	}
+	if name, ok := config.AppNameForID("youtube"); ok {
+		t.Errorf("AppNameForID(youtube) = %q, true, want false", name)
+	}
+}
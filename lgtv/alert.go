@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ShowAlert shows the alert, then waits for the viewer to respond.
+//
+// WebOS does not tell clients which button was pressed, so instead each button launches its AlertButton.AppID,
+// and ShowAlert watches for that app to come to the foreground.
+// This means a button cannot launch the app that is already in the foreground, which ShowAlert refuses,
+// and that launching a button's app any other way while the alert is shown counts as pressing it.
+// Buttons without an AppID only close the alert, which ShowAlert cannot tell until Alert.Timeout has passed.
+// Once Alert.Timeout has passed, ShowAlert closes the alert, or reports it as dismissed if it was already closed.
+func (c *client) ShowAlert(ctx context.Context, alert Alert) (AlertResult, error) {
+	noResult := AlertResult{Button: -1}
+
+	// finished unblocks the handler once ShowAlert stops listening.
+	finished := make(chan struct{})
+	defer close(finished)
+
+	apps := make(chan string)
+	sub, err := c.subscribe(ctx, getApp, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getAppResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+		return func() {
+			select {
+			case apps <- payload.ID:
+			case <-finished:
+			}
+		}, nil
+	})
+	if err != nil {
+		return noResult, err
+	}
+	defer sub.Close()
+
+	// The first App event is the current app, before the alert is shown.
+	var foreground string
+	select {
+	case foreground = <-apps:
+	case <-sub.Done():
+		return noResult, sub.Err()
+	}
+	for _, button := range alert.Buttons {
+		if button.AppID != "" && button.AppID == foreground {
+			return noResult, fmt.Errorf("cannot detect button %q, because its app %v is already in the foreground", button.Label, button.AppID)
+		}
+	}
+
+	alertID, err := c.createAlert(ctx, alert)
+	if err != nil {
+		return noResult, err
+	}
+
+	var timeout <-chan time.Time
+	if alert.Timeout > 0 {
+		timer := time.NewTimer(alert.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		select {
+		case appID := <-apps:
+			for i, button := range alert.Buttons {
+				if button.AppID != "" && button.AppID == appID {
+					return AlertResult{Button: i}, nil
+				}
+			}
+
+		case <-timeout:
+			err := c.closeAlert(ctx, alertID)
+			var tvErr *TVError
+			if errors.As(err, &tvErr) {
+				return AlertResult{Button: -1, Dismissed: true}, nil
+			}
+			if err != nil {
+				return noResult, err
+			}
+			return AlertResult{Button: -1, TimedOut: true}, nil
+
+		case <-sub.Done():
+			// The subscription shares ctx, so it also ends when ctx is done.
+			closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = c.closeAlert(closeCtx, alertID)
+			return noResult, sub.Err()
+		}
+	}
+}
+
+func (c *client) createAlert(ctx context.Context, alert Alert) (string, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	payload := createAlertRequest{
+		Title:    alert.Title,
+		Message:  alert.Message,
+		Type:     "confirm",
+		IsSysReq: true,
+	}
+	for _, button := range alert.Buttons {
+		b := createAlertRequestButton{Label: button.Label}
+		if button.AppID != "" {
+			b.OnClick = lunaLaunch
+			b.Params = struct {
+				ID string `json:"id"`
+			}{button.AppID}
+		}
+		payload.Buttons = append(payload.Buttons, b)
+	}
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     createAlert,
+		Payload: payload,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return "", err
+	}
+
+	rspPayload := createAlertResponse{}
+	if err := json.Unmarshal(rsp.Payload, &rspPayload); err != nil {
+		return "", err
+	}
+	return rspPayload.AlertID, nil
+}
+func (c *client) closeAlert(ctx context.Context, alertID string) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     closeAlert,
+		Payload: closeAlertRequest{alertID},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
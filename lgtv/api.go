@@ -7,6 +7,7 @@ package lgtv
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -21,23 +22,132 @@ type (
 
 		// ListApp lists all apps on the TV.
 		ListApps(context.Context) ([]App, error)
+		// ListRunningApps lists the apps running on the TV, including in the background.
+		ListRunningApps(context.Context) ([]App, error)
 		// App gets the current app on the TV.
 		App(context.Context) (App, error)
 		// SetApp sets the current app ID on the TV.
 		SetApp(context.Context, string) error
+		// LaunchApp launches an app ID on the TV with parameters, e.g. to deep-link into content.
+		LaunchApp(context.Context, string, LaunchParams) error
+		// CloseApp closes a running app ID on the TV.
+		CloseApp(context.Context, string) error
+		// OpenURL opens a URL in the TV's web browser.
+		OpenURL(context.Context, string) error
 		// SubscribeApp listens for App events.
-		SubscribeApp(func(App))
+		SubscribeApp(context.Context, func(App)) (Subscription, error)
+		// StreamApp is like SubscribeApp, but sends App events to a channel, which is closed when the subscription ends.
+		StreamApp(context.Context, StreamOptions) (<-chan App, Subscription, error)
 
 		// Volume gets the current volume on the TV.
 		Volume(context.Context) (Volume, error)
 		// SetVolume sets the current volume percentage on the TV.
 		SetVolume(context.Context, int) error
+		// VolumeUp raises the volume on the TV by one step.
+		VolumeUp(context.Context) error
+		// VolumeDown lowers the volume on the TV by one step.
+		VolumeDown(context.Context) error
+		// SetMute mutes or unmutes the TV.
+		SetMute(context.Context, bool) error
 		// SubscribeVolume listens for Volume events.
-		SubscribeVolume(func(Volume))
+		SubscribeVolume(context.Context, func(Volume)) (Subscription, error)
+		// StreamVolume is like SubscribeVolume, but sends Volume events to a channel, which is closed when the subscription ends.
+		StreamVolume(context.Context, StreamOptions) (<-chan Volume, Subscription, error)
+
+		// SoundOutput gets the current sound output on the TV.
+		SoundOutput(context.Context) (SoundOutput, error)
+		// SetSoundOutput sets the current sound output on the TV.
+		SetSoundOutput(context.Context, SoundOutput) error
+		// SubscribeSoundOutput listens for SoundOutput events.
+		SubscribeSoundOutput(context.Context, func(SoundOutput)) (Subscription, error)
+		// StreamSoundOutput is like SubscribeSoundOutput, but sends SoundOutput events to a channel, which is closed when the subscription ends.
+		StreamSoundOutput(context.Context, StreamOptions) (<-chan SoundOutput, Subscription, error)
+
+		// ListInputs lists all external inputs on the TV, e.g. HDMI ports.
+		ListInputs(context.Context) ([]Input, error)
+		// SetInput sets the current external input ID on the TV.
+		SetInput(context.Context, string) error
+
+		// ListChannels lists all TV channels on the TV.
+		ListChannels(context.Context) ([]Channel, error)
+		// Channel gets the current TV channel on the TV.
+		Channel(context.Context) (Channel, error)
+		// SetChannel sets the current TV channel on the TV,
+		// by Channel.ID if it is set, otherwise by Channel.Number.
+		SetChannel(context.Context, Channel) error
+		// ChannelUp switches to the next TV channel on the TV.
+		ChannelUp(context.Context) error
+		// ChannelDown switches to the previous TV channel on the TV.
+		ChannelDown(context.Context) error
+		// SubscribeChannel listens for Channel events.
+		SubscribeChannel(context.Context, func(Channel)) (Subscription, error)
+		// StreamChannel is like SubscribeChannel, but sends Channel events to a channel, which is closed when the subscription ends.
+		StreamChannel(context.Context, StreamOptions) (<-chan Channel, Subscription, error)
+
+		// Play plays the current media on the TV.
+		Play(context.Context) error
+		// Pause pauses the current media on the TV.
+		Pause(context.Context) error
+		// Stop stops the current media on the TV.
+		Stop(context.Context) error
+		// Rewind rewinds the current media on the TV.
+		Rewind(context.Context) error
+		// FastForward fast-forwards the current media on the TV.
+		FastForward(context.Context) error
+
+		// SubscribeMediaState listens for MediaState events.
+		SubscribeMediaState(context.Context, func(MediaState)) (Subscription, error)
+		// StreamMediaState is like SubscribeMediaState, but sends MediaState events to a channel, which is closed when the subscription ends.
+		StreamMediaState(context.Context, StreamOptions) (<-chan MediaState, Subscription, error)
+
+		// InsertText types text into the TV's on-screen keyboard.
+		InsertText(context.Context, string) error
+		// DeleteCharacters deletes characters before the cursor in the TV's on-screen keyboard.
+		DeleteCharacters(context.Context, int) error
+		// SendEnter presses enter on the TV's on-screen keyboard.
+		SendEnter(context.Context) error
+		// SubscribeKeyboard listens for Keyboard events.
+		SubscribeKeyboard(context.Context, func(Keyboard)) (Subscription, error)
+		// StreamKeyboard is like SubscribeKeyboard, but sends Keyboard events to a channel, which is closed when the subscription ends.
+		StreamKeyboard(context.Context, StreamOptions) (<-chan Keyboard, Subscription, error)
+
+		// ShowToast shows a short notification on the TV.
+		ShowToast(context.Context, string, ToastOptions) error
+		// ShowAlert shows an alert with buttons on the TV, and waits for the viewer to respond.
+		ShowAlert(context.Context, Alert) (AlertResult, error)
+
+		// SystemInfo gets the TV's model, software, and region.
+		SystemInfo(context.Context) (SystemInfo, error)
+
+		// Buttons connects to the TV for remote-control button presses.
+		// The Buttons are closed when the Client is closed.
+		Buttons(context.Context) (Buttons, error)
+		// Pointer connects to the TV for mouse-pointer movement, clicks, and remote-control button presses.
+		// The Pointer is closed when the Client is closed.
+		Pointer(context.Context) (Pointer, error)
+
+		// PowerState gets the current power state of the TV.
+		PowerState(context.Context) (PowerState, error)
+		// SubscribePowerState listens for PowerState events.
+		SubscribePowerState(context.Context, func(PowerState)) (Subscription, error)
+		// StreamPowerState is like SubscribePowerState, but sends PowerState events to a channel, which is closed when the subscription ends.
+		StreamPowerState(context.Context, StreamOptions) (<-chan PowerState, Subscription, error)
+
+		// TurnOffScreen turns off the TV's screen, but leaves the TV on, e.g. for audio.
+		TurnOffScreen(context.Context) error
+		// TurnOnScreen turns the TV's screen back on after TurnOffScreen.
+		TurnOnScreen(context.Context) error
 
 		// TurnOff turns off the TV.
 		TurnOff(context.Context) error
 
+		// Call calls any WebOS API URI, e.g. "ssap://audio/getVolume", with an optional payload.
+		// If out is not nil, the response payload is unmarshaled into it.
+		Call(ctx context.Context, uri string, payload, out interface{}) error
+		// Subscribe subscribes to any WebOS API URI with an optional payload, until ctx is done.
+		// It waits for the TV to accept the subscription, then calls f with each response payload.
+		Subscribe(ctx context.Context, uri string, payload interface{}, f func(json.RawMessage)) (Subscription, error)
+
 		// Wait blocks until the connection to the TV is closed.
 		Wait() error
 
@@ -45,20 +155,180 @@ type (
 		Close() error
 	}
 
+	// Subscription is a subscription to events from the TV, e.g. from Client.SubscribeApp().
+	// Subscribe methods wait for the TV to accept the subscription, and it lasts until its context is done,
+	// Close() is called, the TV returns an error, or the connection closes.
+	// Handlers are called in order, one at a time, and not after Done() is closed.
+	Subscription interface {
+		// Close unsubscribes from the TV, and may be called from a handler.
+		// It does not wait for the subscription to end; wait on Done() for that.
+		Close() error
+		// Done is closed when the subscription has ended and its last handler has returned.
+		Done() <-chan struct{}
+		// Err returns why the subscription ended, or nil if it is active or was closed.
+		Err() error
+	}
+
+	// Buttons presses remote-control buttons on the TV.
+	Buttons interface {
+		// Press presses a button.
+		Press(context.Context, Button) error
+
+		// Close closes the connection to the TV.
+		Close() error
+	}
+
+	// Pointer moves, clicks, and scrolls the mouse pointer on the TV.
+	// Movement and scrolling are coalesced and rate-limited, so they may be called as often as needed.
+	Pointer interface {
+		Buttons
+
+		// Move moves the pointer relative to where it is.
+		Move(dx, dy int) error
+		// Scroll scrolls the view under the pointer.
+		Scroll(dx, dy int) error
+		// Click clicks where the pointer is, after any pending movement.
+		Click(context.Context) error
+	}
+
+	// Button is a remote-control button.
+	Button string
+
 	Options struct {
 		PongTimeout time.Duration
+
+		// Scheme is "ws" or "wss".
+		// If empty, Dial tries ws on DefaultPort, then wss on DefaultSecurePort,
+		// unless CertificateFingerprint or InsecureSkipVerify is set, when it only tries wss.
+		// TVs have self-signed certificates, so without either the wss fallback fails verification,
+		// with an error that includes the certificate's fingerprint to pin.
+		Scheme string
+		// Port overrides the default port for Scheme, and is ignored if Scheme is empty.
+		Port int
+
+		// CertificateFingerprint pins the TV's certificate by its SHA-256 fingerprint in hex, optionally with colons.
+		CertificateFingerprint string
+		// InsecureSkipVerify accepts any certificate from the TV.
+		InsecureSkipVerify bool
 	}
 
+	// StreamOptions are options for Stream* methods.
+	StreamOptions struct {
+		// Buffer is how many events the channel holds for a slow receiver, at least 1.
+		Buffer int
+		// Overflow is what happens to events when the buffer is full, by default OverflowDropOldest.
+		Overflow Overflow
+	}
+	// Overflow is a policy for a full StreamOptions.Buffer.
+	Overflow string
+
 	App struct {
 		Name string `json:"title"`
 		ID   string `json:"id"`
 	}
 
+	// LaunchParams are optional parameters for launching an app.
+	// Which Params an app accepts depends on the app.
+	LaunchParams struct {
+		ContentID string                 `json:"contentId,omitempty"`
+		Params    map[string]interface{} `json:"params,omitempty"`
+	}
+
 	Volume struct {
 		Percent int  `json:"volume"`
 		Muted   bool `json:"muted"`
 	}
 
+	// SoundOutput is where the TV plays sound, e.g. its own speakers or a soundbar.
+	SoundOutput string
+
+	// Input is an external input on the TV, e.g. an HDMI port.
+	// Label is the name the user set on the TV, and AppID is the app that shows the input.
+	Input struct {
+		ID             string `json:"id"`
+		Label          string `json:"label"`
+		Icon           string `json:"icon"`
+		AppID          string `json:"appId"`
+		Connected      bool   `json:"connected"`
+		SignalDetected bool   `json:"signalDetection"`
+	}
+
+	Channel struct {
+		ID     string `json:"channelId"`
+		Number string `json:"channelNumber"`
+		Name   string `json:"channelName"`
+	}
+
+	// MediaState is the state of the foreground media session.
+	MediaState struct {
+		AppID     string
+		PlayState PlayState
+	}
+
+	// PlayState is whether media is playing.
+	PlayState string
+
+	// Keyboard is the state of the TV's on-screen keyboard.
+	Keyboard struct {
+		Open        bool   `json:"focus"`
+		ContentType string `json:"contentType"`
+	}
+
+	// PowerState is whether the TV is on, has its screen off, or is in standby.
+	PowerState string
+
+	// SystemInfo is the TV's model, software, and region.
+	SystemInfo struct {
+		Model string
+		// SerialNumber is only reported by some TVs.
+		SerialNumber string
+
+		// WebOSVersion is the platform, e.g. "webOSTV 4.5", and FirmwareVersion is the build, e.g. "05.30.20".
+		WebOSVersion    string
+		FirmwareVersion string
+
+		Country string
+	}
+
+	// ToastOptions are optional settings for a toast notification.
+	ToastOptions struct {
+		// Icon is an image to show alongside the message, and IconExtension its format, e.g. "png".
+		Icon          []byte
+		IconExtension string
+
+		// OnClickAppID is an app ID to launch if the toast is clicked.
+		OnClickAppID string
+	}
+
+	// Alert is a notification with buttons, shown until the viewer responds.
+	Alert struct {
+		Title   string
+		Message string
+		Buttons []AlertButton
+
+		// Timeout is how long to show the alert for, or forever if 0.
+		Timeout time.Duration
+	}
+
+	AlertButton struct {
+		Label string
+
+		// AppID is an app ID to launch when the button is pressed.
+		// Only buttons with an AppID can be reported in AlertResult.
+		AppID string
+	}
+
+	// AlertResult is how the viewer responded to an Alert.
+	AlertResult struct {
+		// Button is the index of the pressed button in Alert.Buttons, or -1.
+		Button int
+
+		// Dismissed is whether the alert was closed without pressing a button with an AppID.
+		Dismissed bool
+		// TimedOut is whether the alert was closed because its Timeout passed.
+		TimedOut bool
+	}
+
 	// TVError is an error returned by the TV, e.g. about invalid messages.
 	// Connection errors will always return via Client.Err().
 	TVError struct {
@@ -66,11 +336,92 @@ type (
 	}
 )
 
+const (
+	ButtonHome  = Button("HOME")
+	ButtonBack  = Button("BACK")
+	ButtonExit  = Button("EXIT")
+	ButtonInfo  = Button("INFO")
+	ButtonMenu  = Button("MENU")
+	ButtonEnter = Button("ENTER")
+
+	ButtonUp    = Button("UP")
+	ButtonDown  = Button("DOWN")
+	ButtonLeft  = Button("LEFT")
+	ButtonRight = Button("RIGHT")
+
+	Button0 = Button("0")
+	Button1 = Button("1")
+	Button2 = Button("2")
+	Button3 = Button("3")
+	Button4 = Button("4")
+	Button5 = Button("5")
+	Button6 = Button("6")
+	Button7 = Button("7")
+	Button8 = Button("8")
+	Button9 = Button("9")
+
+	ButtonRed    = Button("RED")
+	ButtonGreen  = Button("GREEN")
+	ButtonYellow = Button("YELLOW")
+	ButtonBlue   = Button("BLUE")
+)
+
+const (
+	SoundOutputTVSpeaker        = SoundOutput("tv_speaker")
+	SoundOutputARC              = SoundOutput("external_arc")
+	SoundOutputOptical          = SoundOutput("external_optical")
+	SoundOutputTVSpeakerOptical = SoundOutput("tv_external_speaker")
+	SoundOutputBluetooth        = SoundOutput("bt_soundbar")
+	SoundOutputHeadphones       = SoundOutput("headphone")
+)
+
+const (
+	PlayStatePlaying   = PlayState("playing")
+	PlayStatePaused    = PlayState("paused")
+	PlayStateBuffering = PlayState("buffering")
+	PlayStateStopped   = PlayState("stopped")
+)
+
+const (
+	PowerStateOn        = PowerState("on")
+	PowerStateScreenOff = PowerState("screen-off")
+	PowerStateStandby   = PowerState("standby")
+)
+
+const (
+	// OverflowDropOldest discards the oldest buffered event, so the receiver sees the latest events.
+	OverflowDropOldest = Overflow("drop-oldest")
+	// OverflowDropNewest discards the new event, so the receiver sees the earliest events.
+	OverflowDropNewest = Overflow("drop-newest")
+	// OverflowBlock waits for the receiver, delaying later events to the same subscription.
+	OverflowBlock = Overflow("block")
+)
+
+const (
+	DefaultPort       = 3000
+	DefaultSecurePort = 3001
+)
+
 var (
+	// SoundOutputs are the common sound outputs, although not all TVs support all of them.
+	SoundOutputs = []SoundOutput{
+		SoundOutputTVSpeaker,
+		SoundOutputARC,
+		SoundOutputOptical,
+		SoundOutputTVSpeakerOptical,
+		SoundOutputBluetooth,
+		SoundOutputHeadphones,
+	}
+
 	DefaultOptions = Options{
 		PongTimeout: 10 * time.Second,
 	}
 
+	DefaultStreamOptions = StreamOptions{
+		Buffer:   16,
+		Overflow: OverflowDropOldest,
+	}
+
 	ErrNotConnected = errors.New("not connected to TV")
 )
 
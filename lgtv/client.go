@@ -6,8 +6,15 @@ package lgtv
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,7 +23,8 @@ import (
 
 type (
 	client struct {
-		conn *websocket.Conn
+		conn   *websocket.Conn
+		dialer *websocket.Dialer
 
 		sync.Mutex
 		sequence         int
@@ -25,28 +33,60 @@ type (
 		connectionClosed chan struct{}
 		errors           chan error
 
+		closed    chan struct{}
+		closeOnce sync.Once
+
 		appNameForID map[string]string
 	}
 )
 
 func Dial(ctx context.Context, host string, opts Options) (Client, error) {
-	uri := fmt.Sprintf("ws://%v:3000", host)
+	dialer := &websocket.Dialer{
+		Proxy:            websocket.DefaultDialer.Proxy,
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+		TLSClientConfig:  opts.tlsConfig(host),
+	}
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, uri, nil)
-	if err != nil {
-		return nil, fmt.Errorf("could not dial %v: %w", uri, err)
+	var conn *websocket.Conn
+	var err error
+	switch {
+	case opts.Scheme == "" && opts.wantsTLS():
+		// Never fall back to plain websockets when TLS is configured, or the key would be sent in the clear.
+		conn, err = dial(ctx, dialer, fmt.Sprintf("wss://%v:%v", host, DefaultSecurePort))
+		if err != nil {
+			return nil, err
+		}
+	case opts.Scheme == "":
+		// Newer TVs refuse plain websockets, so fall back to secure ones, which fail with the fingerprint to pin.
+		conn, err = dial(ctx, dialer, fmt.Sprintf("ws://%v:%v", host, DefaultPort))
+		if err != nil {
+			var wssErr error
+			conn, wssErr = dial(ctx, dialer, fmt.Sprintf("wss://%v:%v", host, DefaultSecurePort))
+			if wssErr != nil {
+				return nil, fmt.Errorf("%v; %w", err, wssErr)
+			}
+		}
+	default:
+		conn, err = dial(ctx, dialer, fmt.Sprintf("%v://%v:%v", opts.Scheme, host, opts.port()))
+		if err != nil {
+			return nil, err
+		}
 	}
 	conn.SetPongHandler(func(_ string) error {
 		return conn.SetReadDeadline(time.Now().Add(opts.PongTimeout))
 	})
 
 	c := &client{
-		conn: conn,
+		conn:   conn,
+		dialer: dialer,
 
 		requestChannel:   make(chan *request),
 		responseChannels: map[int]chan *response{},
 		connectionClosed: make(chan struct{}),
-		errors:           make(chan error),
+		// Both readLoop and writeLoop may fail, and neither should block if noöne is waiting.
+		errors: make(chan error, 2),
+
+		closed: make(chan struct{}),
 	}
 	go c.readLoop()
 	go c.writeLoop(opts.pingPeriod())
@@ -54,22 +94,101 @@ func Dial(ctx context.Context, host string, opts Options) (Client, error) {
 	return c, nil
 }
 
+func (o *Options) port() int {
+	if o.Port != 0 {
+		return o.Port
+	}
+	if o.Scheme == "wss" {
+		return DefaultSecurePort
+	}
+	return DefaultPort
+}
+
+// wantsTLS is whether any TLS option is set.
+func (o *Options) wantsTLS() bool {
+	return o.CertificateFingerprint != "" || o.InsecureSkipVerify
+}
+
+// tlsConfig verifies the TV's certificate against CertificateFingerprint if it is set,
+// otherwise as normal for host unless InsecureSkipVerify is set.
+func (o *Options) tlsConfig(host string) *tls.Config {
+	if o.InsecureSkipVerify && o.CertificateFingerprint == "" {
+		return &tls.Config{InsecureSkipVerify: true}
+	}
+
+	want := strings.ToLower(strings.ReplaceAll(o.CertificateFingerprint, ":", ""))
+	return &tls.Config{
+		// TVs have self-signed certificates, so verify them ourselves.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("TV sent no certificate")
+			}
+			got := sha256.Sum256(rawCerts[0])
+			if want != "" {
+				if hex.EncodeToString(got[:]) != want {
+					return fmt.Errorf("TV certificate fingerprint %x does not match %v", got, o.CertificateFingerprint)
+				}
+				return nil
+			}
+
+			// Self-signed certificates never verify, so say how to pin this one.
+			if err := verifyCertificates(host, rawCerts); err != nil {
+				return fmt.Errorf("%w; to trust this TV, set its certificate fingerprint to %x", err, got)
+			}
+			return nil
+		},
+	}
+}
+
+// verifyCertificates verifies a certificate chain for host against the system roots.
+func verifyCertificates(host string, rawCerts [][]byte) error {
+	var certs []*x509.Certificate
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("could not parse TV certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+func dial(ctx context.Context, dialer *websocket.Dialer, uri string) (*websocket.Conn, error) {
+	conn, _, err := dialer.DialContext(ctx, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial %v: %w", uri, err)
+	}
+	return conn, nil
+}
+
 func (c *client) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
 	return c.conn.Close()
 }
 
 func (c *client) Wait() error {
-	defer close(c.errors)
 	return <-c.errors
 }
 
 func (c *client) readLoop() {
-	defer close(c.connectionClosed)
 	defer func() {
 		c.Lock()
 		defer c.Unlock()
-		for _, ch := range c.responseChannels {
+		// Close connectionClosed with the lock held, so newRequest() cannot add a channel that is never closed.
+		close(c.connectionClosed)
+		for id, ch := range c.responseChannels {
 			close(ch)
+			delete(c.responseChannels, id)
 		}
 	}()
 	for {
@@ -89,29 +208,39 @@ func (c *client) readLoop() {
 func (c *client) writeLoop(pingPeriod time.Duration) {
 	ping := time.NewTicker(pingPeriod)
 	defer ping.Stop()
-	defer close(c.requestChannel)
 
 	for {
 		select {
 		case data := <-c.requestChannel:
 			if err := c.conn.WriteJSON(data); err != nil {
 				c.errors <- fmt.Errorf("could not write to websocket%v: %v", data, err)
+				// Closing the connection also stops readLoop.
+				_ = c.conn.Close()
 				return
 			}
 		case <-ping.C:
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				c.errors <- fmt.Errorf("could not ping websocket: %v", err)
+				_ = c.conn.Close()
 				return
 			}
 		case <-c.connectionClosed:
 			_ = c.conn.Close()
-			c.conn = nil
 			log.Print("connection closed")
 			return
 		}
 	}
 }
 
+// send sends a request to the TV, unless the connection has closed,
+// in which case the request's response channel has also been closed.
+func (c *client) send(req *request) {
+	select {
+	case c.requestChannel <- req:
+	case <-c.connectionClosed:
+	}
+}
+
 func (c *client) newRequest() (int, <-chan *response, func()) {
 	c.Lock()
 	defer c.Unlock()
@@ -120,12 +249,22 @@ func (c *client) newRequest() (int, <-chan *response, func()) {
 	c.sequence++
 
 	ch := make(chan *response)
-	c.responseChannels[id] = ch
+	select {
+	case <-c.connectionClosed:
+		// readLoop has already closed every other response channel.
+		close(ch)
+	default:
+		c.responseChannels[id] = ch
+	}
 
 	cancel := func() {
 		c.Lock()
 		defer c.Unlock()
 
+		// The channel is already closed if the connection closed.
+		if _, ok := c.responseChannels[id]; !ok {
+			return
+		}
 		close(ch)
 		delete(c.responseChannels, id)
 	}
@@ -134,9 +273,44 @@ func (c *client) newRequest() (int, <-chan *response, func()) {
 }
 func (c *client) receive(ctx context.Context, ch <-chan *response) (*response, error) {
 	select {
-	case rsp := <-ch:
+	case rsp, ok := <-ch:
+		if !ok {
+			return nil, ErrNotConnected
+		}
 		return rsp, rsp.Err()
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
+
+// drainAndCancel cancels a request that may still be receiving responses, e.g. a subscription.
+// readLoop holds the lock while sending, so keep reading until cancel() closes the channel.
+func drainAndCancel(ch <-chan *response, cancel func()) {
+	go func() {
+		for range ch {
+		}
+	}()
+	cancel()
+}
+
+func (c *client) Call(ctx context.Context, endpoint string, payload, out interface{}) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     uri(endpoint),
+		Payload: payload,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(rsp.Payload, out)
+}
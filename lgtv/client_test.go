@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	var pairs []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		pairs = append(pairs, fingerprint[i:i+2])
+	}
+	colonSeparated := strings.ToUpper(strings.Join(pairs, ":"))
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name: "matching fingerprint",
+			opts: Options{CertificateFingerprint: fingerprint},
+		},
+		{
+			name: "colon-separated fingerprint",
+			opts: Options{CertificateFingerprint: colonSeparated},
+		},
+		{
+			name:    "mismatched fingerprint",
+			opts:    Options{CertificateFingerprint: strings.Repeat("00", sha256.Size)},
+			wantErr: true,
+		},
+		{
+			name:    "no fingerprint",
+			opts:    Options{},
+			wantErr: true,
+		},
+		{
+			name: "insecure",
+			opts: Options{InsecureSkipVerify: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := tls.Dial("tcp", server.Listener.Addr().String(), tt.opts.tlsConfig("127.0.0.1"))
+			if err == nil {
+				conn.Close()
+			}
+			if tt.wantErr && err == nil {
+				t.Fatal("connected, want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("could not connect: %v", err)
+			}
+
+			// Failures say what the fingerprint is, so it can be pinned.
+			if err != nil && !strings.Contains(err.Error(), fingerprint) {
+				t.Errorf("got error %q, want it to include fingerprint %v", err, fingerprint)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type (
+	// fakeTV is a websocket server that registers every client, and otherwise lets the test respond to requests.
+	fakeTV struct {
+		t      *testing.T
+		server *httptest.Server
+
+		requests chan fakeRequest
+
+		sync.Mutex
+		conn *websocket.Conn
+	}
+
+	fakeRequest struct {
+		ID   int         `json:"id"`
+		Type requestType `json:"type"`
+		URI  uri         `json:"uri"`
+	}
+
+	// volumes collects Volume events in order.
+	volumes struct {
+		ch chan int
+	}
+)
+
+// fakeTimeout is how long tests wait for anything to happen.
+const fakeTimeout = 5 * time.Second
+
+func newFakeTV(t *testing.T) *fakeTV {
+	tv := &fakeTV{
+		t:        t,
+		requests: make(chan fakeRequest, 64),
+	}
+	tv.server = httptest.NewServer(http.HandlerFunc(tv.serve))
+	return tv
+}
+
+func (tv *fakeTV) Close() {
+	tv.disconnect()
+	tv.server.Close()
+}
+
+func (tv *fakeTV) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		tv.t.Errorf("could not upgrade websocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	tv.Lock()
+	tv.conn = conn
+	tv.Unlock()
+
+	for {
+		req := fakeRequest{}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.Type == requestTypeRegister {
+			tv.write(conn, map[string]interface{}{
+				"id":      req.ID,
+				"type":    responseTypeRegistered,
+				"payload": registerResponse{ClientKey: "key"},
+			})
+			continue
+		}
+		tv.requests <- req
+	}
+}
+
+func (tv *fakeTV) write(conn *websocket.Conn, msg interface{}) {
+	tv.Lock()
+	defer tv.Unlock()
+	// Writes fail once the client has gone, which some tests expect.
+	_ = conn.WriteJSON(msg)
+}
+
+// options are Options to dial the fake TV with.
+func (tv *fakeTV) options() Options {
+	u, err := url.Parse(tv.server.URL)
+	if err != nil {
+		tv.t.Fatalf("could not parse server URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		tv.t.Fatalf("could not parse server port: %v", err)
+	}
+
+	opts := DefaultOptions
+	opts.Scheme = "ws"
+	opts.Port = port
+	return opts
+}
+
+// dial connects and registers a Client.
+func (tv *fakeTV) dial() Client {
+	ctx, cancel := context.WithTimeout(context.Background(), fakeTimeout)
+	defer cancel()
+
+	c, err := Dial(ctx, "127.0.0.1", tv.options())
+	if err != nil {
+		tv.t.Fatalf("could not dial fake TV: %v", err)
+	}
+
+	if _, err := c.Register(ctx, "key"); err != nil {
+		tv.t.Fatalf("could not register with fake TV: %v", err)
+	}
+	return c
+}
+
+// expect waits for the next request, and checks its type and URI.
+func (tv *fakeTV) expect(typ requestType, u uri) fakeRequest {
+	tv.t.Helper()
+
+	select {
+	case req := <-tv.requests:
+		if req.Type != typ || (u != "" && req.URI != u) {
+			tv.t.Fatalf("got request %+v, want %v %v", req, typ, u)
+		}
+		return req
+	case <-time.After(fakeTimeout):
+		tv.t.Fatalf("timed out waiting for %v %v", typ, u)
+		return fakeRequest{}
+	}
+}
+
+// expectNothing checks that no request arrives for a short while.
+func (tv *fakeTV) expectNothing() {
+	tv.t.Helper()
+
+	select {
+	case req := <-tv.requests:
+		tv.t.Fatalf("got unexpected request %+v", req)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (tv *fakeTV) respond(id int, payload interface{}) {
+	tv.Lock()
+	conn := tv.conn
+	tv.Unlock()
+
+	tv.write(conn, map[string]interface{}{
+		"id":      id,
+		"type":    "response",
+		"payload": payload,
+	})
+}
+
+func (tv *fakeTV) fail(id int, message string) {
+	tv.Lock()
+	conn := tv.conn
+	tv.Unlock()
+
+	tv.write(conn, map[string]interface{}{
+		"id":    id,
+		"type":  responseTypeError,
+		"error": message,
+	})
+}
+
+// disconnect drops the current connection.
+func (tv *fakeTV) disconnect() {
+	tv.Lock()
+	defer tv.Unlock()
+	if tv.conn != nil {
+		_ = tv.conn.Close()
+	}
+}
+
+// subscribeVolume subscribes to volume and answers with the first volume, as the TV does.
+func (tv *fakeTV) subscribeVolume(ctx context.Context, c Client, volume int, f func(Volume)) (Subscription, fakeRequest) {
+	tv.t.Helper()
+
+	type result struct {
+		s   Subscription
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		s, err := c.SubscribeVolume(ctx, f)
+		results <- result{s, err}
+	}()
+
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: volume})
+
+	r := <-results
+	if r.err != nil {
+		tv.t.Fatalf("could not subscribe: %v", r.err)
+	}
+	return r.s, req
+}
+
+// waitDone waits for a subscription to end.
+func waitDone(t *testing.T, s Subscription) {
+	t.Helper()
+
+	select {
+	case <-s.Done():
+	case <-time.After(fakeTimeout):
+		t.Fatal("timed out waiting for subscription to end")
+	}
+}
+
+func newVolumes() *volumes {
+	return &volumes{ch: make(chan int, 64)}
+}
+
+func (v *volumes) handle(volume Volume) {
+	v.ch <- volume.Percent
+}
+
+func (v *volumes) expect(t *testing.T, want ...int) {
+	t.Helper()
+
+	for _, w := range want {
+		select {
+		case got := <-v.ch:
+			if got != w {
+				t.Fatalf("got volume %v, want %v", got, w)
+			}
+		case <-time.After(fakeTimeout):
+			t.Fatalf("timed out waiting for volume %v", w)
+		}
+	}
+}
+
+// expectNone checks that no events arrive for a short while.
+func (v *volumes) expectNone(t *testing.T) {
+	t.Helper()
+
+	select {
+	case got := <-v.ch:
+		t.Fatalf("got unexpected volume %v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
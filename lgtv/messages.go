@@ -25,23 +25,67 @@ type (
 		Error   string          `json:"error"`
 		Payload json.RawMessage `json:"payload"`
 	}
-
 )
 
 const (
-	requestTypeRegister  = requestType("register")
-	requestTypeRequest   = requestType("request")
-	requestTypeSubscribe = requestType("subscribe")
+	requestTypeRegister    = requestType("register")
+	requestTypeRequest     = requestType("request")
+	requestTypeSubscribe   = requestType("subscribe")
+	requestTypeUnsubscribe = requestType("unsubscribe")
 
 	responseTypeError      = responseType("error")
 	responseTypeRegistered = responseType("registered")
 
-	listApps  = uri("ssap://com.webos.applicationManager/listApps")
-	getApp    = uri("ssap://com.webos.applicationManager/getForegroundAppInfo")
-	setApp    = uri("ssap://system.launcher/launch")
-	getVolume = uri("ssap://audio/getVolume")
-	setVolume = uri("ssap://audio/setVolume")
-	turnOff   = uri("ssap://system/turnOff")
+	listApps        = uri("ssap://com.webos.applicationManager/listApps")
+	listRunningApps = uri("ssap://com.webos.applicationManager/running")
+	getApp          = uri("ssap://com.webos.applicationManager/getForegroundAppInfo")
+	setApp          = uri("ssap://system.launcher/launch")
+	closeApp        = uri("ssap://system.launcher/close")
+	openURL         = uri("ssap://system.launcher/open")
+
+	getVolume  = uri("ssap://audio/getVolume")
+	setVolume  = uri("ssap://audio/setVolume")
+	setMute    = uri("ssap://audio/setMute")
+	volumeUp   = uri("ssap://audio/volumeUp")
+	volumeDown = uri("ssap://audio/volumeDown")
+
+	getSoundOutput = uri("ssap://audio/getSoundOutput")
+	setSoundOutput = uri("ssap://audio/changeSoundOutput")
+
+	listInputs   = uri("ssap://tv/getExternalInputList")
+	setInput     = uri("ssap://tv/switchInput")
+	listChannels = uri("ssap://tv/getChannelList")
+	getChannel   = uri("ssap://tv/getCurrentChannel")
+	setChannel   = uri("ssap://tv/openChannel")
+	channelUp    = uri("ssap://tv/channelUp")
+	channelDown  = uri("ssap://tv/channelDown")
+
+	mediaPlay        = uri("ssap://media.controls/play")
+	mediaPause       = uri("ssap://media.controls/pause")
+	mediaStop        = uri("ssap://media.controls/stop")
+	mediaRewind      = uri("ssap://media.controls/rewind")
+	mediaFastForward = uri("ssap://media.controls/fastForward")
+	getMediaState    = uri("ssap://com.webos.media/getForegroundAppInfo")
+
+	insertText       = uri("ssap://com.webos.service.ime/insertText")
+	deleteCharacters = uri("ssap://com.webos.service.ime/deleteCharacters")
+	sendEnter        = uri("ssap://com.webos.service.ime/sendEnterKey")
+	getKeyboard      = uri("ssap://com.webos.service.ime/registerRemoteKeyboard")
+
+	createToast = uri("ssap://system.notifications/createToast")
+	createAlert = uri("ssap://system.notifications/createAlert")
+	closeAlert  = uri("ssap://system.notifications/closeAlert")
+	lunaLaunch  = uri("luna://com.webos.applicationManager/launch")
+
+	getPointerInputSocket = uri("ssap://com.webos.service.networkinput/getPointerInputSocket")
+
+	getSystemInfo   = uri("ssap://system/getSystemInfo")
+	getSoftwareInfo = uri("ssap://com.webos.service.update/getCurrentSWInformation")
+
+	getPowerState = uri("ssap://com.webos.service.tvpower/power/getPowerState")
+	turnOffScreen = uri("ssap://com.webos.service.tvpower/power/turnOffScreen")
+	turnOnScreen  = uri("ssap://com.webos.service.tvpower/power/turnOnScreen")
+	turnOff       = uri("ssap://system/turnOff")
 )
 
 func (rsp *response) Err() error {
@@ -56,10 +100,108 @@ type (
 	listAppsResponse struct {
 		Apps []App `json:"apps"`
 	}
+	listRunningAppsResponse struct {
+		Apps []struct {
+			ID string `json:"id"`
+		} `json:"running"`
+	}
 	getAppResponse struct {
 		ID string `json:"appId"`
 	}
+	closeAppRequest struct {
+		ID string `json:"id"`
+	}
+	openURLRequest struct {
+		Target string `json:"target"`
+	}
+	launchAppRequest struct {
+		ID string `json:"id"`
+		LaunchParams
+	}
 	setVolumeRequest struct {
 		Level int `json:"volume"`
 	}
-)
\ No newline at end of file
+	setMuteRequest struct {
+		Mute bool `json:"mute"`
+	}
+	getSoundOutputResponse struct {
+		SoundOutput SoundOutput `json:"soundOutput"`
+	}
+	setSoundOutputRequest struct {
+		Output SoundOutput `json:"output"`
+	}
+	listInputsResponse struct {
+		Inputs []Input `json:"devices"`
+	}
+	setInputRequest struct {
+		ID string `json:"inputId"`
+	}
+	getMediaStateResponse struct {
+		Sessions []struct {
+			AppID     string `json:"appId"`
+			PlayState string `json:"playState"`
+		} `json:"foregroundAppInfo"`
+	}
+	insertTextRequest struct {
+		Text    string `json:"text"`
+		Replace int    `json:"replace"`
+	}
+	deleteCharactersRequest struct {
+		Count int `json:"count"`
+	}
+	getKeyboardResponse struct {
+		Keyboard Keyboard `json:"currentWidget"`
+	}
+	getPowerStateResponse struct {
+		State      string `json:"state"`
+		Processing string `json:"processing"`
+	}
+	getSystemInfoResponse struct {
+		ModelName    string `json:"modelName"`
+		SerialNumber string `json:"serialNumber"`
+	}
+	getSoftwareInfoResponse struct {
+		ProductName  string `json:"product_name"`
+		MajorVersion string `json:"major_ver"`
+		MinorVersion string `json:"minor_ver"`
+		Country      string `json:"country"`
+	}
+	createToastRequest struct {
+		Message       string                     `json:"message"`
+		IconData      []byte                     `json:"iconData,omitempty"`
+		IconExtension string                     `json:"iconExtension,omitempty"`
+		OnClick       *createToastRequestOnClick `json:"onClick,omitempty"`
+	}
+	createToastRequestOnClick struct {
+		AppID string `json:"appId"`
+	}
+	listChannelsResponse struct {
+		Channels []Channel `json:"channelList"`
+	}
+	setChannelRequest struct {
+		ID     string `json:"channelId,omitempty"`
+		Number string `json:"channelNumber,omitempty"`
+	}
+	createAlertRequest struct {
+		Title    string                     `json:"title,omitempty"`
+		Message  string                     `json:"message"`
+		Modal    bool                       `json:"modal"`
+		Buttons  []createAlertRequestButton `json:"buttons"`
+		Type     string                     `json:"type"`
+		IsSysReq bool                       `json:"isSysReq"`
+	}
+	createAlertRequestButton struct {
+		Label   string      `json:"label"`
+		OnClick uri         `json:"onClick,omitempty"`
+		Params  interface{} `json:"params,omitempty"`
+	}
+	createAlertResponse struct {
+		AlertID string `json:"alertId"`
+	}
+	closeAlertRequest struct {
+		AlertID string `json:"alertId"`
+	}
+	getPointerInputSocketResponse struct {
+		SocketPath string `json:"socketPath"`
+	}
+)
@@ -7,7 +7,7 @@ package lgtv
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 )
 
 func (c *client) ListApps(ctx context.Context) ([]App, error) {
@@ -19,7 +19,7 @@ func (c *client) ListApps(ctx context.Context) ([]App, error) {
 		Type: requestTypeRequest,
 		URI:  listApps,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	rsp, err := c.receive(ctx, rspChan)
 	if err != nil {
@@ -32,6 +32,32 @@ func (c *client) ListApps(ctx context.Context) ([]App, error) {
 	}
 	return payload.Apps, nil
 }
+func (c *client) ListRunningApps(ctx context.Context) ([]App, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  listRunningApps,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := listRunningAppsResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return nil, err
+	}
+	var apps []App
+	for _, app := range payload.Apps {
+		apps = append(apps, App{ID: app.ID})
+	}
+	return apps, nil
+}
 func (c *client) App(ctx context.Context) (App, error) {
 	id, rspChan, cancel := c.newRequest()
 	defer cancel()
@@ -41,7 +67,7 @@ func (c *client) App(ctx context.Context) (App, error) {
 		Type: requestTypeRequest,
 		URI:  getApp,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	rsp, err := c.receive(ctx, rspChan)
 	if err != nil {
@@ -54,33 +80,17 @@ func (c *client) App(ctx context.Context) (App, error) {
 	// TODO: cache a copy of all App names in the client object.
 	return App{ID: payload.ID}, nil
 }
-func (c *client) SubscribeApp(f func(App)) {
-	id, rspChan, _ := c.newRequest()
-
-	req := &request{
-		ID:   id,
-		Type: requestTypeSubscribe,
-		URI:  getApp,
-	}
-	c.requestChannel <- req
-
-	go func() {
-		for rsp := range rspChan {
-			if err := rsp.Err(); err != nil {
-				log.Printf("error recieved from TV waiting for App events: %v", err)
-				continue
-
-			}
-
-			payload := getAppResponse{}
-			if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
-				log.Printf("could not unmarshal App payload: %v", err)
-				continue
-			}
-			app := App{ID: payload.ID}
-			go f(app)
+func (c *client) SubscribeApp(ctx context.Context, f func(App)) (Subscription, error) {
+	return c.subscribe(ctx, getApp, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getAppResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
 		}
-	}()
+		return func() { f(App{ID: payload.ID}) }, nil
+	})
+}
+func (c *client) StreamApp(ctx context.Context, opts StreamOptions) (<-chan App, Subscription, error) {
+	return streamApp(ctx, c, opts)
 }
 func (c *client) SetApp(ctx context.Context, appID string) error {
 	id, rspChan, cancel := c.newRequest()
@@ -94,7 +104,52 @@ func (c *client) SetApp(ctx context.Context, appID string) error {
 			ID string `json:"id"`
 		}{appID},
 	}
-	c.requestChannel <- req
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) LaunchApp(ctx context.Context, appID string, params LaunchParams) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     setApp,
+		Payload: launchAppRequest{appID, params},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) CloseApp(ctx context.Context, appID string) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     closeApp,
+		Payload: closeAppRequest{appID},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) OpenURL(ctx context.Context, url string) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     openURL,
+		Payload: openURLRequest{url},
+	}
+	c.send(req)
 
 	_, err := c.receive(ctx, rspChan)
 	return err
@@ -109,7 +164,7 @@ func (c *client) Volume(ctx context.Context) (Volume, error) {
 		Type: requestTypeRequest,
 		URI:  getVolume,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	rsp, err := c.receive(ctx, rspChan)
 	if err != nil {
@@ -122,49 +177,608 @@ func (c *client) Volume(ctx context.Context) (Volume, error) {
 	}
 	return payload, nil
 }
-func (c *client) SubscribeVolume(f func(Volume)) {
-	id, rspChan, _ := c.newRequest()
+func (c *client) SubscribeVolume(ctx context.Context, f func(Volume)) (Subscription, error) {
+	return c.subscribe(ctx, getVolume, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := Volume{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+		return func() { f(payload) }, nil
+	})
+}
+func (c *client) StreamVolume(ctx context.Context, opts StreamOptions) (<-chan Volume, Subscription, error) {
+	return streamVolume(ctx, c, opts)
+}
+func (c *client) SetVolume(ctx context.Context, volume int) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     setVolume,
+		Payload: setVolumeRequest{volume},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) VolumeUp(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
 
 	req := &request{
 		ID:   id,
-		Type: requestTypeSubscribe,
-		URI:  getVolume,
+		Type: requestTypeRequest,
+		URI:  volumeUp,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) VolumeDown(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  volumeDown,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) SetMute(ctx context.Context, mute bool) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     setMute,
+		Payload: setMuteRequest{mute},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+
+func (c *client) SoundOutput(ctx context.Context) (SoundOutput, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getSoundOutput,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return "", err
+	}
+
+	payload := getSoundOutputResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return "", err
+	}
+	return payload.SoundOutput, nil
+}
+func (c *client) SubscribeSoundOutput(ctx context.Context, f func(SoundOutput)) (Subscription, error) {
+	return c.subscribe(ctx, getSoundOutput, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getSoundOutputResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+		return func() { f(payload.SoundOutput) }, nil
+	})
+}
+func (c *client) StreamSoundOutput(ctx context.Context, opts StreamOptions) (<-chan SoundOutput, Subscription, error) {
+	return streamSoundOutput(ctx, c, opts)
+}
+func (c *client) SetSoundOutput(ctx context.Context, output SoundOutput) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     setSoundOutput,
+		Payload: setSoundOutputRequest{output},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+
+func (c *client) ListInputs(ctx context.Context) ([]Input, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  listInputs,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
-	go func() {
-		for rsp := range rspChan {
-			if err := rsp.Err(); err != nil {
-				log.Printf("error recieved from TV waiting for Volume events: %v", err)
-				continue
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return nil, err
+	}
 
-			}
+	payload := listInputsResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Inputs, nil
+}
+func (c *client) SetInput(ctx context.Context, inputID string) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
 
-			payload := Volume{}
-			if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
-				log.Printf("could not unmarshal Volume payload: %v", err)
-				continue
-			}
-			go f(payload)
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     setInput,
+		Payload: setInputRequest{inputID},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+
+func (c *client) ListChannels(ctx context.Context) ([]Channel, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  listChannels,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := listChannelsResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Channels, nil
+}
+func (c *client) Channel(ctx context.Context) (Channel, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getChannel,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	payload := Channel{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return Channel{}, err
+	}
+	return payload, nil
+}
+func (c *client) SubscribeChannel(ctx context.Context, f func(Channel)) (Subscription, error) {
+	return c.subscribe(ctx, getChannel, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := Channel{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
 		}
-	}()
+		return func() { f(payload) }, nil
+	})
 }
-func (c *client) SetVolume(ctx context.Context, volume int) error {
+func (c *client) StreamChannel(ctx context.Context, opts StreamOptions) (<-chan Channel, Subscription, error) {
+	return streamChannel(ctx, c, opts)
+}
+func (c *client) SetChannel(ctx context.Context, channel Channel) error {
 	id, rspChan, cancel := c.newRequest()
 	defer cancel()
 
+	payload := setChannelRequest{ID: channel.ID}
+	if channel.ID == "" {
+		payload.Number = channel.Number
+	}
+
 	req := &request{
 		ID:      id,
 		Type:    requestTypeRequest,
-		URI:     setVolume,
-		Payload: setVolumeRequest{volume},
+		URI:     setChannel,
+		Payload: payload,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) ChannelUp(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  channelUp,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	_, err := c.receive(ctx, rspChan)
 	return err
 }
+func (c *client) ChannelDown(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  channelDown,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+
+func (c *client) Play(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  mediaPlay,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) Pause(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  mediaPause,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) Stop(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  mediaStop,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) Rewind(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  mediaRewind,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) FastForward(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  mediaFastForward,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) SubscribeMediaState(ctx context.Context, f func(MediaState)) (Subscription, error) {
+	return c.subscribe(ctx, getMediaState, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getMediaStateResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+
+		state := MediaState{PlayState: PlayStateStopped}
+		if len(payload.Sessions) > 0 {
+			state.AppID = payload.Sessions[0].AppID
+			state.PlayState = playStateFromTV(payload.Sessions[0].PlayState)
+		}
+		return func() { f(state) }, nil
+	})
+}
+func (c *client) StreamMediaState(ctx context.Context, opts StreamOptions) (<-chan MediaState, Subscription, error) {
+	return streamMediaState(ctx, c, opts)
+}
+
+// playStateFromTV converts the TV's media play states into PlayStates.
+func playStateFromTV(playState string) PlayState {
+	switch playState {
+	case "playing":
+		return PlayStatePlaying
+	case "paused":
+		return PlayStatePaused
+	case "loaded", "starved":
+		return PlayStateBuffering
+	case "", "unloaded", "stopped":
+		return PlayStateStopped
+	default:
+		return PlayState(playState)
+	}
+}
+
+func (c *client) InsertText(ctx context.Context, text string) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     insertText,
+		Payload: insertTextRequest{Text: text},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) DeleteCharacters(ctx context.Context, count int) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     deleteCharacters,
+		Payload: deleteCharactersRequest{count},
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) SendEnter(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  sendEnter,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) SubscribeKeyboard(ctx context.Context, f func(Keyboard)) (Subscription, error) {
+	return c.subscribe(ctx, getKeyboard, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getKeyboardResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+		return func() { f(payload.Keyboard) }, nil
+	})
+}
+func (c *client) StreamKeyboard(ctx context.Context, opts StreamOptions) (<-chan Keyboard, Subscription, error) {
+	return streamKeyboard(ctx, c, opts)
+}
+
+func (c *client) ShowToast(ctx context.Context, message string, opts ToastOptions) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	payload := createToastRequest{
+		Message:       message,
+		IconData:      opts.Icon,
+		IconExtension: opts.IconExtension,
+	}
+	if opts.OnClickAppID != "" {
+		payload.OnClick = &createToastRequestOnClick{opts.OnClickAppID}
+	}
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeRequest,
+		URI:     createToast,
+		Payload: payload,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+
+func (c *client) SystemInfo(ctx context.Context) (SystemInfo, error) {
+	system, err := c.systemInfo(ctx)
+	if err != nil {
+		return SystemInfo{}, fmt.Errorf("could not get system info: %w", err)
+	}
+	software, err := c.softwareInfo(ctx)
+	if err != nil {
+		return SystemInfo{}, fmt.Errorf("could not get software info: %w", err)
+	}
+
+	firmware := software.MinorVersion
+	if software.MajorVersion != "" {
+		firmware = software.MajorVersion + "." + software.MinorVersion
+	}
 
+	return SystemInfo{
+		Model:           system.ModelName,
+		SerialNumber:    system.SerialNumber,
+		WebOSVersion:    software.ProductName,
+		FirmwareVersion: firmware,
+		Country:         software.Country,
+	}, nil
+}
+func (c *client) systemInfo(ctx context.Context) (getSystemInfoResponse, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getSystemInfo,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return getSystemInfoResponse{}, err
+	}
+
+	payload := getSystemInfoResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return getSystemInfoResponse{}, err
+	}
+	return payload, nil
+}
+func (c *client) softwareInfo(ctx context.Context) (getSoftwareInfoResponse, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getSoftwareInfo,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return getSoftwareInfoResponse{}, err
+	}
+
+	payload := getSoftwareInfoResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return getSoftwareInfoResponse{}, err
+	}
+	return payload, nil
+}
+
+func (c *client) PowerState(ctx context.Context) (PowerState, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getPowerState,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return "", err
+	}
+
+	payload := getPowerStateResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return "", err
+	}
+	return powerStateFromTV(payload.State, payload.Processing), nil
+}
+func (c *client) SubscribePowerState(ctx context.Context, f func(PowerState)) (Subscription, error) {
+	return c.subscribe(ctx, getPowerState, nil, func(rsp json.RawMessage) (func(), error) {
+		payload := getPowerStateResponse{}
+		if err := json.Unmarshal(rsp, &payload); err != nil {
+			return nil, err
+		}
+		return func() { f(powerStateFromTV(payload.State, payload.Processing)) }, nil
+	})
+}
+func (c *client) StreamPowerState(ctx context.Context, opts StreamOptions) (<-chan PowerState, Subscription, error) {
+	return streamPowerState(ctx, c, opts)
+}
+
+// powerStateFromTV converts the TV's power states into PowerStates.
+// The TV reports it is about to turn off with processing, before state changes.
+func powerStateFromTV(state, processing string) PowerState {
+	switch processing {
+	case "Request Power Off", "Request Suspend", "Prepare Suspend":
+		return PowerStateStandby
+	}
+
+	switch state {
+	case "Active", "Screen Saver":
+		return PowerStateOn
+	case "Screen Off":
+		return PowerStateScreenOff
+	case "Active Standby", "Suspend", "Power Off":
+		return PowerStateStandby
+	default:
+		return PowerState(state)
+	}
+}
+
+func (c *client) TurnOffScreen(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  turnOffScreen,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
+func (c *client) TurnOnScreen(ctx context.Context) error {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  turnOnScreen,
+	}
+	c.send(req)
+
+	_, err := c.receive(ctx, rspChan)
+	return err
+}
 func (c *client) TurnOff(ctx context.Context) error {
 	id, rspChan, cancel := c.newRequest()
 	defer cancel()
@@ -174,7 +788,7 @@ func (c *client) TurnOff(ctx context.Context) error {
 		Type: requestTypeRequest,
 		URI:  turnOff,
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	_, err := c.receive(ctx, rspChan)
 	return err
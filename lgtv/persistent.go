@@ -0,0 +1,732 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type (
+	// PersistentOptions are Options for DialPersistent.
+	PersistentOptions struct {
+		Options
+
+		// OnConnect is called after each connection is registered and its subscriptions are replayed.
+		OnConnect func(context.Context, Client)
+		// OnDisconnect is called after each connection closes, with the reason.
+		OnDisconnect func(error)
+		// OnConnectError is called when connecting fails, before retrying with backoff.
+		OnConnectError func(error)
+	}
+
+	// reconnecting is a Client that redials the TV whenever its connection closes.
+	reconnecting struct {
+		host string
+		opts PersistentOptions
+
+		ctx    context.Context
+		cancel func()
+		done   chan struct{}
+
+		sync.Mutex
+		key    string
+		client Client
+
+		// subscriptions are replayed on each new connection.
+		subscriptions []*persistentSubscription
+	}
+
+	// persistentSubscription is a Subscription that is replayed on each new connection until its context is done or the Client is closed.
+	// If the TV rejects a replay or ends it later, for example while its services start after waking, it is retried on the next connection.
+	persistentSubscription struct {
+		ctx       context.Context
+		cancel    func()
+		subscribe func(context.Context, Client) (Subscription, error)
+		done      chan struct{}
+
+		// inner tracks each connection's subscription, so done is only closed once none can call handlers.
+		inner sync.WaitGroup
+
+		sync.Mutex
+		current Subscription
+		err     error
+		closed  bool
+		ended   bool
+	}
+)
+
+const (
+	minReconnectBackoff = 1 * time.Second
+	maxReconnectBackoff = 1 * time.Minute
+
+	reconnectTimeout = 10 * time.Second
+)
+
+// DialPersistent connects to the TV in the background, and reconnects with backoff whenever the connection closes.
+// Each connection is registered with key, and every Subscribe* handler is replayed until its context is done.
+// Subscribe* only returns the TV's error if it rejects the first subscription; later errors are retried on the next connection.
+// While disconnected, requests return ErrNotConnected.
+// Wait() blocks until ctx is done or Close() is called.
+func DialPersistent(ctx context.Context, host, key string, opts PersistentOptions) Client {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &reconnecting{
+		host: host,
+		opts: opts,
+
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+
+		key: key,
+	}
+	go r.run()
+	return r
+}
+
+func (r *reconnecting) run() {
+	defer close(r.done)
+	defer r.endSubscriptions()
+
+	backoff := minReconnectBackoff
+	for {
+		c, err := r.connect()
+		if err != nil {
+			if r.opts.OnConnectError != nil {
+				r.opts.OnConnectError(err)
+			}
+			select {
+			case <-time.After(backoff):
+			case <-r.ctx.Done():
+				return
+			}
+			backoff *= 2
+			if backoff > maxReconnectBackoff {
+				backoff = maxReconnectBackoff
+			}
+			continue
+		}
+		backoff = minReconnectBackoff
+
+		r.Lock()
+		r.client = c
+		subscriptions := append([]*persistentSubscription(nil), r.subscriptions...)
+		r.Unlock()
+
+		// Close the connection as soon as ctx is done, even while replaying, so Close() never waits on the TV.
+		connectionClosed := make(chan struct{})
+		go func() {
+			select {
+			case <-r.ctx.Done():
+				_ = c.Close()
+			case <-connectionClosed:
+			}
+		}()
+
+		// Subscriptions that fail to replay are retried on the next connection.
+		for _, s := range subscriptions {
+			_ = s.resubscribe(r.ctx, c)
+		}
+		if r.opts.OnConnect != nil {
+			r.opts.OnConnect(r.ctx, c)
+		}
+
+		err = c.Wait()
+		close(connectionClosed)
+
+		r.Lock()
+		r.client = nil
+		r.Unlock()
+
+		if r.opts.OnDisconnect != nil {
+			r.opts.OnDisconnect(err)
+		}
+		if r.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (r *reconnecting) connect() (Client, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, reconnectTimeout)
+	defer cancel()
+
+	c, err := Dial(ctx, r.host, r.opts.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	key := r.key
+	r.Unlock()
+
+	key, err = c.Register(ctx, key)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+
+	r.Lock()
+	r.key = key
+	r.Unlock()
+
+	return c, nil
+}
+
+func (r *reconnecting) current() (Client, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.client == nil {
+		return nil, ErrNotConnected
+	}
+	return r.client, nil
+}
+
+// subscribe adds a subscription to replay on each new connection until ctx is done, and subscribes the current connection, if any.
+func (r *reconnecting) subscribe(ctx context.Context, f func(context.Context, Client) (Subscription, error)) (Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &persistentSubscription{
+		ctx:       ctx,
+		cancel:    cancel,
+		subscribe: f,
+		done:      make(chan struct{}),
+	}
+
+	r.Lock()
+	if r.ctx.Err() != nil {
+		r.Unlock()
+		cancel()
+		return nil, ErrNotConnected
+	}
+	r.subscriptions = append(r.subscriptions, s)
+	c := r.client
+	r.Unlock()
+
+	// If the connection drops first, the subscription is replayed on the next one.
+	if c != nil {
+		if err := s.resubscribe(r.ctx, c); err != nil && err != ErrNotConnected {
+			r.unsubscribe(s)
+			cancel()
+			return nil, err
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		r.unsubscribe(s)
+
+		s.Lock()
+		s.ended = true
+		s.Unlock()
+
+		// Each connection's subscription shares ctx, so they are already ending.
+		s.inner.Wait()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// endSubscriptions ends every subscription, once the Client is closed.
+func (r *reconnecting) endSubscriptions() {
+	r.Lock()
+	subscriptions := append([]*persistentSubscription(nil), r.subscriptions...)
+	r.Unlock()
+
+	for _, s := range subscriptions {
+		s.end(ErrNotConnected)
+	}
+}
+
+func (r *reconnecting) unsubscribe(s *persistentSubscription) {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, other := range r.subscriptions {
+		if other == s {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// resubscribe subscribes on c, and gives up if the TV does not answer within reconnectTimeout or before ctx is done.
+func (s *persistentSubscription) resubscribe(ctx context.Context, c Client) error {
+	s.Lock()
+	if s.ended {
+		s.Unlock()
+		return nil
+	}
+	previous := s.current
+	s.inner.Add(1)
+	s.Unlock()
+
+	// Let the previous connection's handlers finish, so events stay in order.
+	if previous != nil {
+		<-previous.Done()
+	}
+
+	current, err := s.subscribeWithTimeout(ctx, c)
+	if err != nil {
+		s.inner.Done()
+		return err
+	}
+	go func() {
+		<-current.Done()
+		s.inner.Done()
+	}()
+
+	s.Lock()
+	s.current = current
+	s.Unlock()
+	return nil
+}
+
+// subscribeWithTimeout subscribes on c until s.ctx is done, but only waits for the TV to accept it until ctx is done or reconnectTimeout passes.
+func (s *persistentSubscription) subscribeWithTimeout(ctx context.Context, c Client) (Subscription, error) {
+	ctx, cancelWait := context.WithTimeout(ctx, reconnectTimeout)
+	defer cancelWait()
+
+	subscriptionCtx, cancel := context.WithCancel(s.ctx)
+	answered := make(chan struct{})
+	timedOut := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+			timedOut <- true
+		case <-answered:
+			timedOut <- false
+		}
+	}()
+
+	current, err := s.subscribe(subscriptionCtx, c)
+	close(answered)
+	if <-timedOut {
+		if err == nil {
+			// It was accepted too late, and is already ending; wait for its handlers.
+			<-current.Done()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		<-current.Done()
+		cancel()
+	}()
+	return current, nil
+}
+
+// end ends the subscription with err, unless it has already ended.
+func (s *persistentSubscription) end(err error) {
+	s.Lock()
+	if s.err == nil && s.ctx.Err() == nil {
+		s.err = err
+	}
+	s.Unlock()
+
+	s.cancel()
+}
+
+func (s *persistentSubscription) Close() error {
+	s.Lock()
+	s.closed = true
+	s.Unlock()
+
+	s.cancel()
+	return nil
+}
+
+func (s *persistentSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *persistentSubscription) Err() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	select {
+	case <-s.done:
+		if s.err != nil {
+			return s.err
+		}
+		return s.ctx.Err()
+	default:
+		return nil
+	}
+}
+
+func (r *reconnecting) Register(ctx context.Context, key string) (string, error) {
+	r.Lock()
+	r.key = key
+	r.Unlock()
+
+	c, err := r.current()
+	if err != nil {
+		return key, err
+	}
+	key, err = c.Register(ctx, key)
+
+	r.Lock()
+	r.key = key
+	r.Unlock()
+
+	return key, err
+}
+
+func (r *reconnecting) Subscribe(ctx context.Context, endpoint string, payload interface{}, f func(json.RawMessage)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.Subscribe(ctx, endpoint, payload, f)
+	})
+}
+
+func (r *reconnecting) Wait() error {
+	<-r.done
+	return nil
+}
+
+func (r *reconnecting) Close() error {
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+func (r *reconnecting) ListApps(ctx context.Context) ([]App, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListApps(ctx)
+}
+func (r *reconnecting) ListRunningApps(ctx context.Context) ([]App, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListRunningApps(ctx)
+}
+func (r *reconnecting) App(ctx context.Context) (App, error) {
+	c, err := r.current()
+	if err != nil {
+		return App{}, err
+	}
+	return c.App(ctx)
+}
+func (r *reconnecting) SetApp(ctx context.Context, appID string) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetApp(ctx, appID)
+}
+func (r *reconnecting) LaunchApp(ctx context.Context, appID string, params LaunchParams) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.LaunchApp(ctx, appID, params)
+}
+func (r *reconnecting) CloseApp(ctx context.Context, appID string) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.CloseApp(ctx, appID)
+}
+func (r *reconnecting) OpenURL(ctx context.Context, url string) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.OpenURL(ctx, url)
+}
+func (r *reconnecting) SubscribeApp(ctx context.Context, f func(App)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeApp(ctx, f)
+	})
+}
+func (r *reconnecting) StreamApp(ctx context.Context, opts StreamOptions) (<-chan App, Subscription, error) {
+	return streamApp(ctx, r, opts)
+}
+func (r *reconnecting) Volume(ctx context.Context) (Volume, error) {
+	c, err := r.current()
+	if err != nil {
+		return Volume{}, err
+	}
+	return c.Volume(ctx)
+}
+func (r *reconnecting) SetVolume(ctx context.Context, volume int) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetVolume(ctx, volume)
+}
+func (r *reconnecting) VolumeUp(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.VolumeUp(ctx)
+}
+func (r *reconnecting) VolumeDown(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.VolumeDown(ctx)
+}
+func (r *reconnecting) SetMute(ctx context.Context, mute bool) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetMute(ctx, mute)
+}
+func (r *reconnecting) SubscribeVolume(ctx context.Context, f func(Volume)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeVolume(ctx, f)
+	})
+}
+func (r *reconnecting) StreamVolume(ctx context.Context, opts StreamOptions) (<-chan Volume, Subscription, error) {
+	return streamVolume(ctx, r, opts)
+}
+func (r *reconnecting) SoundOutput(ctx context.Context) (SoundOutput, error) {
+	c, err := r.current()
+	if err != nil {
+		return "", err
+	}
+	return c.SoundOutput(ctx)
+}
+func (r *reconnecting) SetSoundOutput(ctx context.Context, output SoundOutput) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetSoundOutput(ctx, output)
+}
+func (r *reconnecting) SubscribeSoundOutput(ctx context.Context, f func(SoundOutput)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeSoundOutput(ctx, f)
+	})
+}
+func (r *reconnecting) StreamSoundOutput(ctx context.Context, opts StreamOptions) (<-chan SoundOutput, Subscription, error) {
+	return streamSoundOutput(ctx, r, opts)
+}
+func (r *reconnecting) ListInputs(ctx context.Context) ([]Input, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListInputs(ctx)
+}
+func (r *reconnecting) SetInput(ctx context.Context, inputID string) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetInput(ctx, inputID)
+}
+func (r *reconnecting) ListChannels(ctx context.Context) ([]Channel, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListChannels(ctx)
+}
+func (r *reconnecting) Channel(ctx context.Context) (Channel, error) {
+	c, err := r.current()
+	if err != nil {
+		return Channel{}, err
+	}
+	return c.Channel(ctx)
+}
+func (r *reconnecting) SetChannel(ctx context.Context, channel Channel) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SetChannel(ctx, channel)
+}
+func (r *reconnecting) ChannelUp(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.ChannelUp(ctx)
+}
+func (r *reconnecting) ChannelDown(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.ChannelDown(ctx)
+}
+func (r *reconnecting) SubscribeChannel(ctx context.Context, f func(Channel)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeChannel(ctx, f)
+	})
+}
+func (r *reconnecting) StreamChannel(ctx context.Context, opts StreamOptions) (<-chan Channel, Subscription, error) {
+	return streamChannel(ctx, r, opts)
+}
+func (r *reconnecting) Play(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.Play(ctx)
+}
+func (r *reconnecting) Pause(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.Pause(ctx)
+}
+func (r *reconnecting) Stop(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.Stop(ctx)
+}
+func (r *reconnecting) Rewind(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.Rewind(ctx)
+}
+func (r *reconnecting) FastForward(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.FastForward(ctx)
+}
+func (r *reconnecting) SubscribeMediaState(ctx context.Context, f func(MediaState)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeMediaState(ctx, f)
+	})
+}
+func (r *reconnecting) StreamMediaState(ctx context.Context, opts StreamOptions) (<-chan MediaState, Subscription, error) {
+	return streamMediaState(ctx, r, opts)
+}
+func (r *reconnecting) InsertText(ctx context.Context, text string) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.InsertText(ctx, text)
+}
+func (r *reconnecting) DeleteCharacters(ctx context.Context, count int) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.DeleteCharacters(ctx, count)
+}
+func (r *reconnecting) SendEnter(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.SendEnter(ctx)
+}
+func (r *reconnecting) SubscribeKeyboard(ctx context.Context, f func(Keyboard)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribeKeyboard(ctx, f)
+	})
+}
+func (r *reconnecting) StreamKeyboard(ctx context.Context, opts StreamOptions) (<-chan Keyboard, Subscription, error) {
+	return streamKeyboard(ctx, r, opts)
+}
+func (r *reconnecting) ShowToast(ctx context.Context, message string, opts ToastOptions) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.ShowToast(ctx, message, opts)
+}
+func (r *reconnecting) ShowAlert(ctx context.Context, alert Alert) (AlertResult, error) {
+	c, err := r.current()
+	if err != nil {
+		return AlertResult{}, err
+	}
+	return c.ShowAlert(ctx, alert)
+}
+func (r *reconnecting) SystemInfo(ctx context.Context) (SystemInfo, error) {
+	c, err := r.current()
+	if err != nil {
+		return SystemInfo{}, err
+	}
+	return c.SystemInfo(ctx)
+}
+func (r *reconnecting) Buttons(ctx context.Context) (Buttons, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.Buttons(ctx)
+}
+func (r *reconnecting) Pointer(ctx context.Context) (Pointer, error) {
+	c, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.Pointer(ctx)
+}
+func (r *reconnecting) PowerState(ctx context.Context) (PowerState, error) {
+	c, err := r.current()
+	if err != nil {
+		return "", err
+	}
+	return c.PowerState(ctx)
+}
+func (r *reconnecting) SubscribePowerState(ctx context.Context, f func(PowerState)) (Subscription, error) {
+	return r.subscribe(ctx, func(ctx context.Context, c Client) (Subscription, error) {
+		return c.SubscribePowerState(ctx, f)
+	})
+}
+func (r *reconnecting) StreamPowerState(ctx context.Context, opts StreamOptions) (<-chan PowerState, Subscription, error) {
+	return streamPowerState(ctx, r, opts)
+}
+func (r *reconnecting) TurnOffScreen(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.TurnOffScreen(ctx)
+}
+func (r *reconnecting) TurnOnScreen(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.TurnOnScreen(ctx)
+}
+func (r *reconnecting) TurnOff(ctx context.Context) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.TurnOff(ctx)
+}
+func (r *reconnecting) Call(ctx context.Context, endpoint string, payload interface{}, out interface{}) error {
+	c, err := r.current()
+	if err != nil {
+		return err
+	}
+	return c.Call(ctx, endpoint, payload, out)
+}
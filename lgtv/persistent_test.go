@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPersistentSubscriptionReplay(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+	defer r.Close()
+
+	waitConnected(t, connected)
+
+	got := newVolumes()
+	s, _ := tv.subscribeVolume(context.Background(), r, 1, got.handle)
+	got.expect(t, 1)
+
+	tv.disconnect()
+
+	// The subscription is replayed on the new connection.
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: 2})
+	waitConnected(t, connected)
+	got.expect(t, 2)
+
+	tv.respond(req.ID, Volume{Percent: 3})
+	got.expect(t, 3)
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("could not close subscription: %v", err)
+	}
+	if unsubscribe := tv.expect(requestTypeUnsubscribe, ""); unsubscribe.ID != req.ID {
+		t.Errorf("unsubscribed from %v, want %v", unsubscribe.ID, req.ID)
+	}
+	waitDone(t, s)
+	if err := s.Err(); err != nil {
+		t.Errorf("got error %v after Close, want nil", err)
+	}
+
+	// Closed subscriptions are not replayed.
+	tv.disconnect()
+	waitConnected(t, connected)
+	tv.expectNothing()
+}
+
+func TestPersistentSubscriptionDisconnectWhileSubscribing(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+	defer r.Close()
+
+	waitConnected(t, connected)
+
+	got := newVolumes()
+	results := make(chan error, 1)
+	go func() {
+		_, err := r.SubscribeVolume(context.Background(), got.handle)
+		results <- err
+	}()
+
+	// The connection drops before the TV answers, so the subscription waits for the next one.
+	tv.expect(requestTypeSubscribe, getVolume)
+	tv.disconnect()
+	if err := <-results; err != nil {
+		t.Fatalf("could not subscribe: %v", err)
+	}
+
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: 1})
+	got.expect(t, 1)
+}
+
+func TestPersistentCloseWhileReplaying(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+
+	waitConnected(t, connected)
+	s, _ := tv.subscribeVolume(context.Background(), r, 1, func(Volume) {})
+
+	// The TV never answers the replay.
+	tv.disconnect()
+	tv.expect(requestTypeSubscribe, getVolume)
+
+	closed := make(chan struct{})
+	go func() {
+		_ = r.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(fakeTimeout):
+		t.Fatal("timed out waiting for Close")
+	}
+	waitDone(t, s)
+}
+
+func TestPersistentSubscriptionRejected(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+	defer r.Close()
+
+	waitConnected(t, connected)
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := r.SubscribeVolume(context.Background(), func(Volume) {})
+		results <- err
+	}()
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.fail(req.ID, "401 insufficient permissions")
+	tv.expect(requestTypeUnsubscribe, "")
+
+	var tvErr *TVError
+	if err := <-results; !errors.As(err, &tvErr) {
+		t.Errorf("got error %v, want a TVError", err)
+	}
+
+	// Rejected subscriptions are not replayed.
+	tv.disconnect()
+	waitConnected(t, connected)
+	tv.expectNothing()
+}
+
+func TestPersistentSubscriptionRejectedOnReplay(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+	defer r.Close()
+
+	waitConnected(t, connected)
+	got := newVolumes()
+	s, _ := tv.subscribeVolume(context.Background(), r, 1, got.handle)
+	got.expect(t, 1)
+
+	// The TV may reject subscriptions while it is still starting up.
+	tv.disconnect()
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.fail(req.ID, "service not ready")
+	tv.expect(requestTypeUnsubscribe, "")
+	waitConnected(t, connected)
+
+	tv.disconnect()
+	req = tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: 2})
+	got.expect(t, 2)
+
+	select {
+	case <-s.Done():
+		t.Errorf("subscription ended with %v, want it to be retried", s.Err())
+	default:
+	}
+}
+
+func TestPersistentSubscriptionTVError(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+	defer r.Close()
+
+	waitConnected(t, connected)
+	got := newVolumes()
+	s, req := tv.subscribeVolume(context.Background(), r, 1, got.handle)
+	got.expect(t, 1)
+
+	tv.fail(req.ID, "something went wrong")
+	tv.expect(requestTypeUnsubscribe, "")
+
+	tv.disconnect()
+	req = tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: 2})
+	got.expect(t, 2)
+
+	select {
+	case <-s.Done():
+		t.Errorf("subscription ended with %v, want it to be retried", s.Err())
+	default:
+	}
+}
+
+func TestPersistentClose(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	r, connected := dialPersistent(t, tv)
+
+	waitConnected(t, connected)
+	s, _ := tv.subscribeVolume(context.Background(), r, 1, func(Volume) {})
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("could not close client: %v", err)
+	}
+	waitDone(t, s)
+	if err := s.Err(); err != ErrNotConnected {
+		t.Errorf("got error %v, want %v", err, ErrNotConnected)
+	}
+}
+
+// dialPersistent dials the fake TV, and signals each time it connects.
+func dialPersistent(t *testing.T, tv *fakeTV) (Client, <-chan struct{}) {
+	connected := make(chan struct{}, 1)
+	r := DialPersistent(context.Background(), "127.0.0.1", "key", PersistentOptions{
+		Options: tv.options(),
+		OnConnect: func(context.Context, Client) {
+			connected <- struct{}{}
+		},
+	})
+	return r, connected
+}
+
+func waitConnected(t *testing.T, connected <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-connected:
+	case <-time.After(fakeTimeout):
+		t.Fatal("timed out waiting to connect")
+	}
+}
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type (
+	// pointer is a connection to the TV's pointer input socket,
+	// a secondary websocket for remote-control buttons and the mouse pointer.
+	pointer struct {
+		conn *websocket.Conn
+
+		sync.Mutex
+		closed    chan struct{}
+		closeOnce sync.Once
+
+		// Movement and scrolling since the last flush, coalesced to at most one of each per pointerInterval.
+		moveX, moveY     int
+		scrollX, scrollY int
+	}
+)
+
+const (
+	// pointerInterval is the shortest time between pointer movements sent to the TV.
+	pointerInterval = 50 * time.Millisecond
+	// flushTimeout bounds each periodic flush, so a stalled TV cannot hold the lock from Press and Click forever.
+	flushTimeout = 5 * pointerInterval
+)
+
+func (c *client) Buttons(ctx context.Context) (Buttons, error) {
+	return c.pointer(ctx)
+}
+func (c *client) Pointer(ctx context.Context) (Pointer, error) {
+	return c.pointer(ctx)
+}
+
+func (c *client) pointer(ctx context.Context) (*pointer, error) {
+	id, rspChan, cancel := c.newRequest()
+	defer cancel()
+
+	req := &request{
+		ID:   id,
+		Type: requestTypeRequest,
+		URI:  getPointerInputSocket,
+	}
+	c.send(req)
+
+	rsp, err := c.receive(ctx, rspChan)
+	if err != nil {
+		return nil, err
+	}
+	payload := getPointerInputSocketResponse{}
+	if err := json.Unmarshal(rsp.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(ctx, c.dialer, payload.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pointer{
+		conn:   conn,
+		closed: make(chan struct{}),
+	}
+	go p.readLoop()
+	go p.flushLoop()
+
+	// The pointer socket lives only as long as the main connection.
+	go func() {
+		select {
+		case <-c.connectionClosed:
+			_ = p.Close()
+		case <-c.closed:
+			_ = p.Close()
+		case <-p.closed:
+		}
+	}()
+
+	return p, nil
+}
+
+func (p *pointer) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		err = p.conn.Close()
+	})
+	return err
+}
+
+// readLoop discards everything the TV sends, but must run to handle pings and closes.
+func (p *pointer) readLoop() {
+	for {
+		if _, _, err := p.conn.ReadMessage(); err != nil {
+			_ = p.Close()
+			return
+		}
+	}
+}
+
+// flushLoop sends coalesced movement and scrolling to the TV every pointerInterval.
+func (p *pointer) flushLoop() {
+	ticker := time.NewTicker(pointerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			p.Lock()
+			err := p.flushLocked(ctx)
+			p.Unlock()
+			cancel()
+			if err != nil {
+				_ = p.Close()
+				return
+			}
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+// flushLocked sends any pending movement and scrolling, and must be called with the lock held.
+func (p *pointer) flushLocked(ctx context.Context) error {
+	if p.moveX != 0 || p.moveY != 0 {
+		msg := fmt.Sprintf("type:move\ndx:%d\ndy:%d\ndown:0\n\n", p.moveX, p.moveY)
+		p.moveX, p.moveY = 0, 0
+		if err := p.writeLocked(ctx, msg); err != nil {
+			return err
+		}
+	}
+	if p.scrollX != 0 || p.scrollY != 0 {
+		msg := fmt.Sprintf("type:scroll\ndx:%d\ndy:%d\n\n", p.scrollX, p.scrollY)
+		p.scrollX, p.scrollY = 0, 0
+		if err := p.writeLocked(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLocked sends a message to the TV, and must be called with the lock held.
+func (p *pointer) writeLocked(ctx context.Context, msg string) error {
+	select {
+	case <-p.closed:
+		return ErrNotConnected
+	default:
+	}
+
+	// A zero deadline, i.e. no deadline on ctx, means no write deadline.
+	deadline, _ := ctx.Deadline()
+	if err := p.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	return p.conn.WriteMessage(websocket.TextMessage, []byte(msg))
+}
+
+func (p *pointer) Press(ctx context.Context, button Button) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if err := p.flushLocked(ctx); err != nil {
+		return err
+	}
+	return p.writeLocked(ctx, fmt.Sprintf("type:button\nname:%v\n\n", button))
+}
+
+func (p *pointer) Move(dx, dy int) error {
+	p.Lock()
+	defer p.Unlock()
+
+	select {
+	case <-p.closed:
+		return ErrNotConnected
+	default:
+	}
+
+	p.moveX += dx
+	p.moveY += dy
+	return nil
+}
+func (p *pointer) Scroll(dx, dy int) error {
+	p.Lock()
+	defer p.Unlock()
+
+	select {
+	case <-p.closed:
+		return ErrNotConnected
+	default:
+	}
+
+	p.scrollX += dx
+	p.scrollY += dy
+	return nil
+}
+func (p *pointer) Click(ctx context.Context) error {
+	p.Lock()
+	defer p.Unlock()
+
+	// Click where the pointer is meant to be, not where it was.
+	if err := p.flushLocked(ctx); err != nil {
+		return err
+	}
+	return p.writeLocked(ctx, "type:click\n\n")
+}
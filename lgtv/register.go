@@ -65,7 +65,7 @@ func (c *client) Register(ctx context.Context, key string) (string, error) {
 			ClientKey: key,
 		},
 	}
-	c.requestChannel <- req
+	c.send(req)
 
 	rsp, err := c.receive(ctx, rspChan)
 	if err != nil {
@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type (
+	// streamSubscription is the Subscription for a Stream* channel.
+	streamSubscription struct {
+		Subscription
+
+		cancel func()
+		done   chan struct{}
+
+		sync.Mutex
+		closed bool
+	}
+)
+
+// stream subscribes with a handler that sends each event to ch, a buffered channel, according to opts.Overflow.
+// ch is closed once the subscription ends, which is after its last handler returns.
+func stream(ctx context.Context, ch interface{}, opts StreamOptions, subscribe func(context.Context, func(interface{})) (Subscription, error)) (Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	out := reflect.ValueOf(ch)
+
+	var send func(interface{})
+	switch opts.Overflow {
+	case OverflowDropOldest, "":
+		send = func(event interface{}) {
+			// Handlers are called one at a time, so each receive makes room for this send.
+			for !out.TrySend(reflect.ValueOf(event)) {
+				out.TryRecv()
+			}
+		}
+	case OverflowDropNewest:
+		send = func(event interface{}) {
+			out.TrySend(reflect.ValueOf(event))
+		}
+	case OverflowBlock:
+		send = func(event interface{}) {
+			reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: out, Send: reflect.ValueOf(event)},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			})
+		}
+	default:
+		cancel()
+		return nil, fmt.Errorf("unknown overflow policy %q", opts.Overflow)
+	}
+
+	inner, err := subscribe(ctx, send)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &streamSubscription{
+		Subscription: inner,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+	go func() {
+		<-inner.Done()
+		cancel()
+		out.Close()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+func (opts StreamOptions) buffer() int {
+	if opts.Buffer < 1 {
+		return 1
+	}
+	return opts.Buffer
+}
+
+func (s *streamSubscription) Close() error {
+	s.Lock()
+	s.closed = true
+	s.Unlock()
+
+	// Cancelling first unblocks a handler waiting on OverflowBlock.
+	s.cancel()
+	return s.Subscription.Close()
+}
+
+func (s *streamSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *streamSubscription) Err() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	return s.Subscription.Err()
+}
+
+func streamApp(ctx context.Context, c Client, opts StreamOptions) (<-chan App, Subscription, error) {
+	ch := make(chan App, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeApp(ctx, func(app App) { send(app) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamVolume(ctx context.Context, c Client, opts StreamOptions) (<-chan Volume, Subscription, error) {
+	ch := make(chan Volume, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeVolume(ctx, func(volume Volume) { send(volume) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamSoundOutput(ctx context.Context, c Client, opts StreamOptions) (<-chan SoundOutput, Subscription, error) {
+	ch := make(chan SoundOutput, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeSoundOutput(ctx, func(output SoundOutput) { send(output) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamChannel(ctx context.Context, c Client, opts StreamOptions) (<-chan Channel, Subscription, error) {
+	ch := make(chan Channel, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeChannel(ctx, func(channel Channel) { send(channel) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamMediaState(ctx context.Context, c Client, opts StreamOptions) (<-chan MediaState, Subscription, error) {
+	ch := make(chan MediaState, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeMediaState(ctx, func(state MediaState) { send(state) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamKeyboard(ctx context.Context, c Client, opts StreamOptions) (<-chan Keyboard, Subscription, error) {
+	ch := make(chan Keyboard, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribeKeyboard(ctx, func(keyboard Keyboard) { send(keyboard) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
+func streamPowerState(ctx context.Context, c Client, opts StreamOptions) (<-chan PowerState, Subscription, error) {
+	ch := make(chan PowerState, opts.buffer())
+	s, err := stream(ctx, ch, opts, func(ctx context.Context, send func(interface{})) (Subscription, error) {
+		return c.SubscribePowerState(ctx, func(state PowerState) { send(state) })
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, s, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStreamOverflow(t *testing.T) {
+	tests := []struct {
+		overflow Overflow
+		want     []int
+	}{
+		{OverflowDropOldest, []int{3, 4}},
+		{OverflowDropNewest, []int{1, 2}},
+		{OverflowBlock, []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.overflow), func(t *testing.T) {
+			tv := newFakeTV(t)
+			defer tv.Close()
+			c := tv.dial()
+			defer c.Close()
+
+			ch, s, req := tv.streamVolume(c, StreamOptions{Buffer: 2, Overflow: tt.overflow})
+
+			tv.respond(req.ID, Volume{Percent: 2})
+			tv.respond(req.ID, Volume{Percent: 3})
+			tv.respond(req.ID, Volume{Percent: 4})
+			tv.fail(req.ID, "the end")
+
+			// Nothing reads until the subscription ends, so the buffer overflows.
+			// Blocked streams cannot end until they are read.
+			if tt.overflow != OverflowBlock {
+				waitDone(t, s)
+			}
+
+			var got []int
+			for v := range ch {
+				got = append(got, v.Percent)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got volumes %v, want %v", got, tt.want)
+			}
+
+			waitDone(t, s)
+			var tvErr *TVError
+			if err := s.Err(); !errors.As(err, &tvErr) {
+				t.Errorf("got error %v, want a TVError", err)
+			}
+		})
+	}
+}
+
+func TestStreamCloseWhileBlocked(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	ch, s, req := tv.streamVolume(c, StreamOptions{Buffer: 1, Overflow: OverflowBlock})
+	tv.respond(req.ID, Volume{Percent: 2})
+	tv.respond(req.ID, Volume{Percent: 3})
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("could not close stream: %v", err)
+	}
+	tv.expect(requestTypeUnsubscribe, "")
+	waitDone(t, s)
+	if err := s.Err(); err != nil {
+		t.Errorf("got error %v after Close, want nil", err)
+	}
+
+	// The channel is closed, even though events were waiting to be received.
+	for range ch {
+	}
+}
+
+func TestStreamUnknownOverflow(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	if _, _, err := c.StreamVolume(context.Background(), StreamOptions{Overflow: "explode"}); err == nil {
+		t.Error("got no error for unknown overflow policy")
+	}
+	tv.expectNothing()
+}
+
+// streamVolume streams volume and answers with the first volume, 1.
+func (tv *fakeTV) streamVolume(c Client, opts StreamOptions) (<-chan Volume, Subscription, fakeRequest) {
+	tv.t.Helper()
+
+	type result struct {
+		ch  <-chan Volume
+		s   Subscription
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		ch, s, err := c.StreamVolume(context.Background(), opts)
+		results <- result{ch, s, err}
+	}()
+
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.respond(req.ID, Volume{Percent: 1})
+
+	r := <-results
+	if r.err != nil {
+		tv.t.Fatalf("could not stream volume: %v", r.err)
+	}
+	return r.ch, r.s, req
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+type (
+	subscription struct {
+		c      *client
+		id     int
+		closed chan struct{}
+		done   chan struct{}
+
+		closeOnce sync.Once
+
+		// ready is signalled whenever events are added or the subscription ends.
+		ready chan struct{}
+		// delivered is closed once deliverLoop has stopped calling handlers.
+		delivered chan struct{}
+
+		sync.Mutex
+		err    error
+		events []func()
+		ended  bool
+	}
+)
+
+func (c *client) Subscribe(ctx context.Context, endpoint string, payload interface{}, f func(json.RawMessage)) (Subscription, error) {
+	return c.subscribe(ctx, uri(endpoint), payload, func(rsp json.RawMessage) (func(), error) {
+		return func() { f(rsp) }, nil
+	})
+}
+
+// subscribe subscribes to a URI, and waits for the TV to accept it.
+// Every response payload, including the first, is decoded into an event, and if decoding fails the subscription ends.
+// Events are called in order, one at a time, so a slow handler delays later events but never the connection.
+func (c *client) subscribe(ctx context.Context, u uri, payload interface{}, decode func(json.RawMessage) (func(), error)) (Subscription, error) {
+	id, rspChan, cancel := c.newRequest()
+
+	req := &request{
+		ID:      id,
+		Type:    requestTypeSubscribe,
+		URI:     u,
+		Payload: payload,
+	}
+	c.send(req)
+
+	// The first response says whether the TV accepted the subscription.
+	var event func()
+	rsp, err := c.receive(ctx, rspChan)
+	if err == nil {
+		event, err = decode(rsp.Payload)
+	}
+	if err != nil {
+		c.unsubscribe(id)
+		drainAndCancel(rspChan, cancel)
+		return nil, err
+	}
+
+	s := &subscription{
+		c:      c,
+		id:     id,
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+
+		ready:     make(chan struct{}, 1),
+		delivered: make(chan struct{}),
+
+		events: []func(){event},
+	}
+	go s.loop(ctx, rspChan, cancel, decode)
+	go s.deliverLoop(ctx)
+	return s, nil
+}
+
+func (c *client) unsubscribe(id int) {
+	c.send(&request{
+		ID:   id,
+		Type: requestTypeUnsubscribe,
+	})
+}
+
+func (s *subscription) loop(ctx context.Context, rspChan <-chan *response, cancel func(), decode func(json.RawMessage) (func(), error)) {
+	err := func() error {
+		for {
+			select {
+			case rsp, ok := <-rspChan:
+				if !ok {
+					return ErrNotConnected
+				}
+				if err := rsp.Err(); err != nil {
+					return err
+				}
+				event, err := decode(rsp.Payload)
+				if err != nil {
+					return err
+				}
+				s.push(event)
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-s.closed:
+				return nil
+			}
+		}
+	}()
+
+	s.Lock()
+	s.err = err
+	s.ended = true
+	s.Unlock()
+	s.signal()
+
+	if err != ErrNotConnected {
+		s.c.unsubscribe(s.id)
+	}
+	drainAndCancel(rspChan, cancel)
+
+	<-s.delivered
+	close(s.done)
+}
+
+func (s *subscription) push(event func()) {
+	s.Lock()
+	s.events = append(s.events, event)
+	s.Unlock()
+	s.signal()
+}
+
+func (s *subscription) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// deliverLoop calls each event in turn.
+// Once the subscription ends, it delivers any remaining events, unless it was closed or its context is done.
+func (s *subscription) deliverLoop(ctx context.Context) {
+	defer close(s.delivered)
+
+	for {
+		s.Lock()
+		if len(s.events) == 0 {
+			ended := s.ended
+			s.Unlock()
+			if ended {
+				return
+			}
+
+			select {
+			case <-s.ready:
+			case <-ctx.Done():
+				return
+			case <-s.closed:
+				return
+			}
+			continue
+		}
+		event := s.events[0]
+		s.events[0] = nil
+		s.events = s.events[1:]
+		s.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closed:
+			return
+		default:
+		}
+		event()
+	}
+}
+
+func (s *subscription) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (s *subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *subscription) Err() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.err
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	got := newVolumes()
+	s, req := tv.subscribeVolume(context.Background(), c, 1, got.handle)
+
+	for volume := 2; volume <= 10; volume++ {
+		tv.respond(req.ID, Volume{Percent: volume})
+	}
+	got.expect(t, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("could not close subscription: %v", err)
+	}
+	if unsubscribe := tv.expect(requestTypeUnsubscribe, ""); unsubscribe.ID != req.ID {
+		t.Errorf("unsubscribed from %v, want %v", unsubscribe.ID, req.ID)
+	}
+	waitDone(t, s)
+	if err := s.Err(); err != nil {
+		t.Errorf("got error %v after Close, want nil", err)
+	}
+
+	tv.respond(req.ID, Volume{Percent: 11})
+	got.expectNone(t)
+}
+
+func TestSubscribeRejected(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.SubscribeVolume(context.Background(), func(Volume) {})
+		errs <- err
+	}()
+
+	req := tv.expect(requestTypeSubscribe, getVolume)
+	tv.fail(req.ID, "401 insufficient permissions")
+
+	var tvErr *TVError
+	if err := <-errs; !errors.As(err, &tvErr) {
+		t.Errorf("got error %v, want a TVError", err)
+	}
+	tv.expect(requestTypeUnsubscribe, "")
+}
+
+func TestSubscriptionCloseFromHandler(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	subscriptions := make(chan Subscription, 1)
+	got := newVolumes()
+	s, req := tv.subscribeVolume(context.Background(), c, 1, func(v Volume) {
+		got.handle(v)
+		if v.Percent == 2 {
+			_ = (<-subscriptions).Close()
+		}
+	})
+	subscriptions <- s
+
+	tv.respond(req.ID, Volume{Percent: 2})
+	got.expect(t, 1, 2)
+	tv.expect(requestTypeUnsubscribe, "")
+	waitDone(t, s)
+}
+
+func TestSubscriptionContext(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, _ := tv.subscribeVolume(ctx, c, 1, func(Volume) {})
+
+	cancel()
+	tv.expect(requestTypeUnsubscribe, "")
+	waitDone(t, s)
+	if err := s.Err(); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSubscriptionTVError(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	s, req := tv.subscribeVolume(context.Background(), c, 1, func(Volume) {})
+
+	tv.fail(req.ID, "something went wrong")
+	waitDone(t, s)
+
+	var tvErr *TVError
+	if err := s.Err(); !errors.As(err, &tvErr) {
+		t.Errorf("got error %v, want a TVError", err)
+	}
+	tv.expect(requestTypeUnsubscribe, "")
+}
+
+func TestSubscriptionDisconnect(t *testing.T) {
+	tv := newFakeTV(t)
+	defer tv.Close()
+	c := tv.dial()
+	defer c.Close()
+
+	s, _ := tv.subscribeVolume(context.Background(), c, 1, func(Volume) {})
+
+	tv.disconnect()
+	waitDone(t, s)
+	if err := s.Err(); err != ErrNotConnected {
+		t.Errorf("got error %v, want %v", err, ErrNotConnected)
+	}
+}
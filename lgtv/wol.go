@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2020 Ethel Morgan
+//
+// SPDX-License-Identifier: MIT
+
+package lgtv
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+const (
+	defaultWakeOnLANAddr = "255.255.255.255"
+	defaultWakeOnLANPort = "9"
+)
+
+// WakeOnLAN turns on a TV by sending a Wake-on-LAN magic packet for its MAC address.
+// The broadcast address may omit the port, which defaults to 9, or be empty, which defaults to 255.255.255.255.
+func WakeOnLAN(mac, broadcastAddr string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return fmt.Errorf("could not parse MAC address: %w", err)
+	}
+	if len(hw) != 6 {
+		return fmt.Errorf("MAC address must be 6 bytes, got %v", len(hw))
+	}
+
+	if broadcastAddr == "" {
+		broadcastAddr = defaultWakeOnLANAddr
+	}
+	if _, _, err := net.SplitHostPort(broadcastAddr); err != nil {
+		broadcastAddr = net.JoinHostPort(broadcastAddr, defaultWakeOnLANPort)
+	}
+
+	// A magic packet is 6 bytes of 0xFF, followed by the MAC address 16 times.
+	packet := append(bytes.Repeat([]byte{0xFF}, 6), bytes.Repeat(hw, 16)...)
+
+	conn, err := net.Dial("udp", broadcastAddr)
+	if err != nil {
+		return fmt.Errorf("could not dial %v: %w", broadcastAddr, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("could not send magic packet: %w", err)
+	}
+	return nil
+}